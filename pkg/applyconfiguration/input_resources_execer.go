@@ -0,0 +1,55 @@
+package applyconfiguration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryinputresources"
+	"sigs.k8s.io/yaml"
+)
+
+// inputResourcesTimeout bounds `<binary> input-resources`.  It only prints a static list, so anything slower is hung.
+const inputResourcesTimeout = 5 * time.Second
+
+// ExecInputResources runs `<binaryPath> input-resources` and parses the InputResources it prints to stdout.
+func ExecInputResources(ctx context.Context, binaryPath string) (*libraryinputresources.InputResources, error) {
+	processCtx, cancel := context.WithTimeout(ctx, inputResourcesTimeout)
+	defer cancel()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(processCtx, binaryPath, "input-resources")
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed running %q input-resources: %w stderr: %v", binaryPath, err, stderr.String())
+	}
+
+	inputResources := &libraryinputresources.InputResources{}
+	if err := yaml.Unmarshal(stdout.Bytes(), inputResources); err != nil {
+		return nil, fmt.Errorf("unable to parse input resources from %q: %w", binaryPath, err)
+	}
+	return inputResources, nil
+}
+
+// LoadInputResources reads the InputResources from inputResourcesFile if it is set and otherwise runs
+// ExecInputResources on operatorBinary.  Commands use it to honor their --input-resources and --operator-binary flags.
+func LoadInputResources(ctx context.Context, inputResourcesFile, operatorBinary string) (*libraryinputresources.InputResources, error) {
+	if len(inputResourcesFile) == 0 {
+		return ExecInputResources(ctx, operatorBinary)
+	}
+
+	inputResourcesBytes, err := os.ReadFile(inputResourcesFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read input resources %q: %w", inputResourcesFile, err)
+	}
+	inputResources := &libraryinputresources.InputResources{}
+	if err := yaml.Unmarshal(inputResourcesBytes, inputResources); err != nil {
+		return nil, fmt.Errorf("unable to parse input resources %q: %w", inputResourcesFile, err)
+	}
+	return inputResources, nil
+}
@@ -1,7 +1,9 @@
 package create_input_resources
 
 import (
+	from_cluster "github.com/openshift/multi-operator-manager/pkg/cmd/multi-operator-manager/create-input-resources/from-cluster"
 	from_must_gather "github.com/openshift/multi-operator-manager/pkg/cmd/multi-operator-manager/create-input-resources/from-must-gather"
+	from_resource_watch "github.com/openshift/multi-operator-manager/pkg/cmd/multi-operator-manager/create-input-resources/from-resource-watch"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 )
@@ -12,9 +14,9 @@ func NewCreateInputResourcesCommand(streams genericiooptions.IOStreams) *cobra.C
 		SilenceErrors: true,
 	}
 	cmd.AddCommand(
-		// TODO add a command that can take a kubeconfig and read the content from a live cluster
+		from_cluster.NewCreateInputResourcesFromClusterCommand(streams),
 		from_must_gather.NewCreateInputResourcesFromMustGatherCommand(streams),
-		// TODO add command that can take failure output from a MOM apply-configuration call (includes resourceVersion) and walk through a resource-watch git repo to build the depends-on output for making an integration test.
+		from_resource_watch.NewCreateInputResourcesFromResourceWatchCommand(streams),
 	)
 	return cmd
 }
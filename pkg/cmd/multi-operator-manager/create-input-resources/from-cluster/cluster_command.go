@@ -0,0 +1,120 @@
+package from_cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/multi-operator-manager/pkg/applyconfiguration"
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryinputresources"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type FromClusterFlags struct {
+	Kubeconfig string
+
+	// OutputDirectory is the directory to where output should be stored
+	OutputDirectory string
+
+	InputResourcesFile string
+	OperatorBinary     string
+
+	Streams genericiooptions.IOStreams
+}
+
+func NewCreateInputResourcesFromClusterFlags(streams genericiooptions.IOStreams) *FromClusterFlags {
+	return &FromClusterFlags{
+		Streams: streams,
+	}
+}
+
+func NewCreateInputResourcesFromClusterCommand(streams genericiooptions.IOStreams) *cobra.Command {
+	f := NewCreateInputResourcesFromClusterFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "from-cluster",
+		Short: "Read the input resources for an operator from a live cluster and write the minimal output to disk in must-gather format.",
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			o, err := f.ToOptions(ctx)
+			if err != nil {
+				return err
+			}
+			if err := o.Run(ctx); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *FromClusterFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.Kubeconfig, "kubeconfig", f.Kubeconfig, "The kubeconfig used to connect to the cluster. Defaults to the usual KUBECONFIG loading rules.")
+	flags.StringVar(&f.OutputDirectory, "output-dir", f.OutputDirectory, "The directory where the output is stored.")
+	flags.StringVar(&f.InputResourcesFile, "input-resources", f.InputResourcesFile, "The file where pertinent resources are stored.")
+	flags.StringVar(&f.OperatorBinary, "operator-binary", f.OperatorBinary, "Path to the operator binary to call <operator-binary> input-resources.")
+}
+
+func (f *FromClusterFlags) Validate() error {
+	if len(f.OutputDirectory) == 0 {
+		return fmt.Errorf("--output-dir is required")
+	}
+	if (len(f.InputResourcesFile) == 0) == (len(f.OperatorBinary) == 0) {
+		return fmt.Errorf("exactly one of --input-resources and --operator-binary is required")
+	}
+	return nil
+}
+
+func (f *FromClusterFlags) ToOptions(ctx context.Context) (*FromClusterOptions, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = f.Kubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load kubeconfig: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating dynamicClient: %w", err)
+	}
+
+	inputResources, err := applyconfiguration.LoadInputResources(ctx, f.InputResourcesFile, f.OperatorBinary)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FromClusterOptions{
+		DynamicClient:   dynamicClient,
+		InputResources:  inputResources,
+		OutputDirectory: f.OutputDirectory,
+		Streams:         f.Streams,
+	}, nil
+}
+
+// FromClusterOptions holds the resolved values for from-cluster.  The DynamicClient is an interface so that
+// it can be backed by a fake or a local API server for testing.
+type FromClusterOptions struct {
+	DynamicClient   dynamic.Interface
+	InputResources  *libraryinputresources.InputResources
+	OutputDirectory string
+
+	Streams genericiooptions.IOStreams
+}
+
+func (o *FromClusterOptions) Run(ctx context.Context) error {
+	return libraryinputresources.WriteRequiredInputAndOperandResourcesFromClient(ctx, o.InputResources, o.DynamicClient, o.OutputDirectory)
+}
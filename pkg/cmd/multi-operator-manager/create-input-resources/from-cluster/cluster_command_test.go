@@ -0,0 +1,90 @@
+package from_cluster
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/multi-operator-manager/pkg/applyconfiguration"
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryinputresources"
+	"github.com/openshift/multi-operator-manager/pkg/library/librarymustgather"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+func TestRunMultipleVersions(t *testing.T) {
+	testDir := filepath.Join("..", "..", "..", "..", "library", "libraryinputresources", "test-data", "multiple-versions-01")
+	ctx := context.Background()
+
+	// the must-gather stands in for the cluster, served through manifestclient.
+	mustGatherFS, err := librarymustgather.NewFS(filepath.Join(testDir, "input-dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dynamicClient, err := libraryinputresources.NewDynamicClientFromFS(mustGatherFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputResources, err := applyconfiguration.LoadInputResources(ctx, filepath.Join(testDir, "input-resources.yaml"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := &FromClusterOptions{
+		DynamicClient:   dynamicClient,
+		InputResources:  inputResources,
+		OutputDirectory: t.TempDir(),
+		Streams:         genericiooptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}},
+	}
+	if err := o.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"beta-policy.yaml", "ga-policy.yaml"} {
+		expected, err := os.ReadFile(filepath.Join(testDir, "expected-output", "cluster-scoped-resources", "admissionregistration.k8s.io", "validatingadmissionpolicies", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := os.ReadFile(filepath.Join(o.OutputDirectory, "cluster-scoped-resources", "admissionregistration.k8s.io", "validatingadmissionpolicies", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(expected, actual) {
+			t.Errorf("%v differs from expected:\n%s", name, actual)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   FromClusterFlags
+		wantErr string
+	}{
+		{
+			name:    "missing output-dir",
+			flags:   FromClusterFlags{InputResourcesFile: "input-resources.yaml"},
+			wantErr: "--output-dir is required",
+		},
+		{
+			name:    "both input sources",
+			flags:   FromClusterFlags{OutputDirectory: "out", InputResourcesFile: "input-resources.yaml", OperatorBinary: "operator"},
+			wantErr: "exactly one of --input-resources and --operator-binary is required",
+		},
+		{
+			name:  "valid",
+			flags: FromClusterFlags{OutputDirectory: "out", InputResourcesFile: "input-resources.yaml"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.flags.Validate()
+			switch {
+			case len(tt.wantErr) == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case len(tt.wantErr) > 0 && (err == nil || err.Error() != tt.wantErr):
+				t.Fatalf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
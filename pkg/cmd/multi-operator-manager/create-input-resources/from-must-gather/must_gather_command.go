@@ -2,8 +2,11 @@ package from_must_gather
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/openshift/multi-operator-manager/pkg/applyconfiguration"
 	"github.com/openshift/multi-operator-manager/pkg/library/libraryinputresources"
+	"github.com/openshift/multi-operator-manager/pkg/library/librarymustgather"
 	"os"
 	"sigs.k8s.io/yaml"
 
@@ -21,6 +24,13 @@ type FromMustGatherFlags struct {
 	InputResourcesFile string
 	OperatorBinary     string
 
+	// MissingInputsFile is where the report of requested, but absent, resources is written.
+	MissingInputsFile string
+	// FailOnMissing returns an error if any requested resource is absent.
+	FailOnMissing bool
+	// ExplainFile is where the reason every resource was selected is written.
+	ExplainFile string
+
 	Streams genericiooptions.IOStreams
 }
 
@@ -59,10 +69,13 @@ func NewCreateInputResourcesFromMustGatherCommand(streams genericiooptions.IOStr
 }
 
 func (f *FromMustGatherFlags) BindFlags(flags *pflag.FlagSet) {
-	flags.StringVar(&f.MustGatherDirectory, "must-gather-dir", f.MustGatherDirectory, "The directory where must-gather output is located.")
+	flags.StringVar(&f.MustGatherDirectory, "must-gather-dir", f.MustGatherDirectory, "The directory where must-gather output is located. A .tar, .tar.gz, or .tgz must-gather archive is also accepted.")
 	flags.StringVar(&f.OutputDirectory, "output-dir", f.OutputDirectory, "The directory where the output is stored.")
 	flags.StringVar(&f.InputResourcesFile, "input-resources", f.InputResourcesFile, "The file where pertinent resources are stored.")
-	flags.StringVar(&f.OperatorBinary, "operator-binary", f.OperatorBinary, "Path to the operator binary to call <operator-binary> depends-on.")
+	flags.StringVar(&f.OperatorBinary, "operator-binary", f.OperatorBinary, "Path to the operator binary to call <operator-binary> input-resources.")
+	flags.StringVar(&f.MissingInputsFile, "missing-inputs-file", f.MissingInputsFile, "The file where resources that were requested, but not present in the must-gather, are listed. For instance, missing-inputs.yaml.")
+	flags.BoolVar(&f.FailOnMissing, "fail-on-missing", f.FailOnMissing, "Fail if any requested resource is not present in the must-gather. Nothing is written to --output-dir in that case, but --missing-inputs-file and --explain are still written.")
+	flags.StringVar(&f.ExplainFile, "explain", f.ExplainFile, "The file where the rule that selected each written resource, and every rule that selected nothing, is listed. For instance, explain.yaml.")
 }
 
 func (f *FromMustGatherFlags) Validate() error {
@@ -72,27 +85,75 @@ func (f *FromMustGatherFlags) Validate() error {
 	if len(f.OutputDirectory) == 0 {
 		return fmt.Errorf("--output-dir is required")
 	}
-	switch {
-	case len(f.InputResourcesFile) == 0 && len(f.OperatorBinary) == 0:
-		return fmt.Errorf("exactly one of --pertinent-resources and --operator-binary is required")
-	case len(f.InputResourcesFile) == 0 && len(f.OperatorBinary) != 0:
-		return fmt.Errorf("not yet wired through")
-	case len(f.InputResourcesFile) != 0 && len(f.OperatorBinary) == 0:
-	case len(f.InputResourcesFile) != 0 && len(f.OperatorBinary) != 0:
-		return fmt.Errorf("exactly one of --pertinent-resources and --operator-binary is required")
+	if (len(f.InputResourcesFile) == 0) == (len(f.OperatorBinary) == 0) {
+		return fmt.Errorf("exactly one of --input-resources and --operator-binary is required")
 	}
 	return nil
 }
 
 func (f *FromMustGatherFlags) Run(ctx context.Context) error {
-	pertinentResourcesBytes, err := os.ReadFile(f.InputResourcesFile)
+	pertinentResources, err := applyconfiguration.LoadInputResources(ctx, f.InputResourcesFile, f.OperatorBinary)
+	if err != nil {
+		return err
+	}
+	// archives are read into memory, so read the must-gather once for both clients.
+	mustGatherFS, err := librarymustgather.NewFS(f.MustGatherDirectory)
+	if err != nil {
+		return err
+	}
+	discoveryClient, err := libraryinputresources.NewDiscoveryClientFromFS(mustGatherFS)
+	if err != nil {
+		return err
+	}
+	warnings, errs := libraryinputresources.ValidateInputResourcesAgainstDiscovery(pertinentResources, discoveryClient)
+	for _, warning := range warnings {
+		fmt.Fprintf(f.Streams.ErrOut, "warning: %v\n", warning)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("input resources do not match discovery in %q: %w", f.MustGatherDirectory, errors.Join(errs...))
+	}
+
+	dynamicClient, err := libraryinputresources.NewDynamicClientFromFS(mustGatherFS)
+	if err != nil {
+		return err
+	}
+	actualResources, report, err := libraryinputresources.GetRequiredInputAndOperandResourcesFromClientWithReport(ctx, pertinentResources, dynamicClient)
 	if err != nil {
-		return fmt.Errorf("unable to read pertinent resources %q: %w", f.InputResourcesFile, err)
+		return err
 	}
-	pertinentResources := &libraryinputresources.InputResources{}
-	if err := yaml.Unmarshal(pertinentResourcesBytes, &pertinentResources); err != nil {
-		return fmt.Errorf("unable to parse pertinent resources %q: %w", f.InputResourcesFile, err)
+	if len(f.MissingInputsFile) > 0 {
+		if err := writeYAML(f.MissingInputsFile, report.Missing); err != nil {
+			return err
+		}
 	}
+	if len(f.ExplainFile) > 0 {
+		if err := writeYAML(f.ExplainFile, report.Explained); err != nil {
+			return err
+		}
+	}
+	missingInputs := report.Missing
+	// fail before writing so that an incomplete output-dir is never left behind.
+	if len(missingInputs.MissingResources) > 0 && f.FailOnMissing {
+		return fmt.Errorf("%d requested input resources were not present in %q", len(missingInputs.MissingResources), f.MustGatherDirectory)
+	}
+
+	if err := libraryinputresources.WriteResources(actualResources, f.OutputDirectory); err != nil {
+		return err
+	}
+	if len(missingInputs.MissingResources) > 0 {
+		fmt.Fprintf(f.Streams.ErrOut, "%d requested input resources were not present in %q\n", len(missingInputs.MissingResources), f.MustGatherDirectory)
+	}
+
+	return nil
+}
 
-	return libraryinputresources.WriteRequiredInputResourcesFromMustGather(ctx, pertinentResources, f.MustGatherDirectory, f.OutputDirectory)
+func writeYAML(filename string, obj interface{}) error {
+	objBytes, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("unable to serialize %q: %w", filename, err)
+	}
+	if err := os.WriteFile(filename, objBytes, 0644); err != nil {
+		return fmt.Errorf("unable to write %q: %w", filename, err)
+	}
+	return nil
 }
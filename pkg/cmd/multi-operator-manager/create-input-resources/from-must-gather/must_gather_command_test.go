@@ -0,0 +1,89 @@
+package from_must_gather
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+func TestRunMultipleVersions(t *testing.T) {
+	testDir := filepath.Join("..", "..", "..", "..", "library", "libraryinputresources", "test-data", "multiple-versions-01")
+	errOut := &bytes.Buffer{}
+	f := NewCreateInputResourcesFromMustGatherFlags(genericiooptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: errOut})
+	f.MustGatherDirectory = filepath.Join(testDir, "input-dir")
+	f.InputResourcesFile = filepath.Join(testDir, "input-resources.yaml")
+	f.OutputDirectory = t.TempDir()
+	f.FailOnMissing = true
+
+	if err := f.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(errOut.String(), "v1 differs from v1beta1") {
+		t.Errorf("expected a warning about mixed versions, got %q", errOut.String())
+	}
+
+	for _, name := range []string{"beta-policy.yaml", "ga-policy.yaml"} {
+		expected, err := os.ReadFile(filepath.Join(testDir, "expected-output", "cluster-scoped-resources", "admissionregistration.k8s.io", "validatingadmissionpolicies", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := os.ReadFile(filepath.Join(f.OutputDirectory, "cluster-scoped-resources", "admissionregistration.k8s.io", "validatingadmissionpolicies", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(expected, actual) {
+			t.Errorf("%v differs from expected:\n%s", name, actual)
+		}
+	}
+}
+
+func TestRunFailOnMissingWritesNoOutput(t *testing.T) {
+	testDir := filepath.Join("..", "..", "..", "..", "library", "libraryinputresources", "test-data", "multiple-versions-01")
+	inputResourcesFile := filepath.Join(t.TempDir(), "input-resources.yaml")
+	inputResources := `applyConfigurationResources:
+  exactResources:
+    - group: admissionregistration.k8s.io
+      version: v1
+      resource: validatingadmissionpolicies
+      name: ga-policy
+    - group: admissionregistration.k8s.io
+      version: v1
+      resource: validatingadmissionpolicies
+      name: missing-policy
+`
+	if err := os.WriteFile(inputResourcesFile, []byte(inputResources), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := NewCreateInputResourcesFromMustGatherFlags(genericiooptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+	f.MustGatherDirectory = filepath.Join(testDir, "input-dir")
+	f.InputResourcesFile = inputResourcesFile
+	f.OutputDirectory = filepath.Join(t.TempDir(), "output")
+	f.MissingInputsFile = filepath.Join(t.TempDir(), "missing-inputs.yaml")
+	f.FailOnMissing = true
+
+	if err := f.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	err := f.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1 requested input resources were not present") {
+		t.Fatalf("expected a missing resource error, got %v", err)
+	}
+	if _, err := os.Stat(f.OutputDirectory); !os.IsNotExist(err) {
+		t.Errorf("expected no output directory, got %v", err)
+	}
+	missingInputs, err := os.ReadFile(f.MissingInputsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(missingInputs), "missing-policy") {
+		t.Errorf("expected missing-policy to be reported, got:\n%s", missingInputs)
+	}
+}
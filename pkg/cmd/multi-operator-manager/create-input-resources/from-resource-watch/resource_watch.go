@@ -0,0 +1,247 @@
+package from_resource_watch
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryapplyconfiguration"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// observedResourceVersion is a resourceVersion that an apply-configuration run was working from.
+type observedResourceVersion struct {
+	ResourceType    schema.GroupVersionResource
+	Namespace       string
+	Name            string
+	ResourceVersion string
+}
+
+func (o observedResourceVersion) String() string {
+	return fmt.Sprintf("%s.%s.%s/%s[%s]@%s", o.ResourceType.Resource, o.ResourceType.Version, o.ResourceType.Group, o.Name, o.Namespace, o.ResourceVersion)
+}
+
+// resourceWatchFilename is the location of a single resource in a resource-watch repository.
+// This is the same layout used for individual files in a must-gather, so a checkout can be pruned directly.
+func (o observedResourceVersion) resourceWatchFilename() string {
+	group := o.ResourceType.Group
+	if len(group) == 0 {
+		group = "core"
+	}
+	if len(o.Namespace) == 0 {
+		return filepath.Join("cluster-scoped-resources", group, o.ResourceType.Resource, o.Name+".yaml")
+	}
+	return filepath.Join("namespaces", o.Namespace, group, o.ResourceType.Resource, o.Name+".yaml")
+}
+
+// observedResourceVersionsFromMutations finds every mutation body that carries a resourceVersion.
+// Updates carry the resourceVersion of the object the controller read, which pins the cluster state it saw.
+func observedResourceVersionsFromMutations(allDesiredMutations libraryapplyconfiguration.AllDesiredMutationsGetter) ([]observedResourceVersion, error) {
+	errs := []error{}
+	byKey := map[string]observedResourceVersion{}
+	for _, clusterType := range sets.List(libraryapplyconfiguration.AllClusterTypes) {
+		mutations := allDesiredMutations.MutationsForClusterType(clusterType)
+		if mutations == nil {
+			continue
+		}
+		for _, request := range mutations.Requests().AllRequests() {
+			serializedRequest := request.GetSerializedRequest()
+			if len(serializedRequest.Body) == 0 || len(serializedRequest.Name) == 0 {
+				continue
+			}
+			body := struct {
+				Metadata struct {
+					ResourceVersion string `json:"resourceVersion"`
+				} `json:"metadata"`
+			}{}
+			if err := yaml.Unmarshal(serializedRequest.Body, &body); err != nil {
+				// patches are not always objects, there's nothing for us to find in those.
+				continue
+			}
+			if len(body.Metadata.ResourceVersion) == 0 {
+				continue
+			}
+
+			observed := observedResourceVersion{
+				ResourceType:    serializedRequest.ResourceType,
+				Namespace:       serializedRequest.Namespace,
+				Name:            serializedRequest.Name,
+				ResourceVersion: body.Metadata.ResourceVersion,
+			}
+			key := observed.resourceWatchFilename()
+			if existing, ok := byKey[key]; ok && existing.ResourceVersion != observed.ResourceVersion {
+				errs = append(errs, fmt.Errorf("%v was observed at more than one resourceVersion: %q and %q", key, existing.ResourceVersion, observed.ResourceVersion))
+				continue
+			}
+			byKey[key] = observed
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	ret := []observedResourceVersion{}
+	for _, key := range sets.List(sets.KeySet(byKey)) {
+		ret = append(ret, byKey[key])
+	}
+	return ret, nil
+}
+
+type resourceWatchCommit struct {
+	Hash string
+	Time time.Time
+}
+
+// findMatchingCommit walks the history of repoDir and returns the newest commit where every observed resource
+// is at its observed resourceVersion.
+func findMatchingCommit(ctx context.Context, repoDir string, observed []observedResourceVersion) (*resourceWatchCommit, error) {
+	if len(observed) == 0 {
+		return nil, fmt.Errorf("no resourceVersions were found to match against")
+	}
+
+	// oldest first so that commit indexes increase with time
+	logOutput, err := git(ctx, repoDir, "log", "--reverse", "--format=%H %cI")
+	if err != nil {
+		return nil, err
+	}
+	commits := []resourceWatchCommit{}
+	commitIndexes := map[string]int{}
+	for _, line := range strings.Split(strings.TrimSpace(logOutput), "\n") {
+		hash, timeString, found := strings.Cut(line, " ")
+		if !found {
+			continue
+		}
+		commitTime, err := time.Parse(time.RFC3339, timeString)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse time for commit %q: %w", hash, err)
+		}
+		commitIndexes[hash] = len(commits)
+		commits = append(commits, resourceWatchCommit{Hash: hash, Time: commitTime})
+	}
+
+	// every resource is at the observed resourceVersion in the range [start, end).  The answer is the intersection.
+	start, end := 0, len(commits)
+	errs := []error{}
+	for _, curr := range observed {
+		currStart, currEnd, err := findResourceVersionRange(ctx, repoDir, commitIndexes, len(commits), curr)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		start = max(start, currStart)
+		end = min(end, currEnd)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if start >= end {
+		return nil, fmt.Errorf("no single commit in %q has all of: %v", repoDir, observed)
+	}
+
+	return &commits[end-1], nil
+}
+
+// findResourceVersionRange returns the range of commit indexes where the file for observed has its resourceVersion.
+func findResourceVersionRange(ctx context.Context, repoDir string, commitIndexes map[string]int, numCommits int, observed observedResourceVersion) (int, int, error) {
+	filename := observed.resourceWatchFilename()
+	logOutput, err := git(ctx, repoDir, "log", "--reverse", "--format=%H", "--", filename)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	start := -1
+	for _, hash := range strings.Fields(logOutput) {
+		index, ok := commitIndexes[hash]
+		if !ok {
+			continue
+		}
+		if start >= 0 {
+			// the next change to the file ends the range.
+			return start, index, nil
+		}
+
+		content, err := git(ctx, repoDir, "show", hash+":"+filepath.ToSlash(filename))
+		if err != nil {
+			// the file was deleted in this commit
+			continue
+		}
+		obj := struct {
+			Metadata struct {
+				ResourceVersion string `json:"resourceVersion"`
+			} `json:"metadata"`
+		}{}
+		if err := yaml.Unmarshal([]byte(content), &obj); err != nil {
+			return 0, 0, fmt.Errorf("unable to parse %v at %v: %w", filename, hash, err)
+		}
+		if obj.Metadata.ResourceVersion == observed.ResourceVersion {
+			start = index
+		}
+	}
+	if start >= 0 {
+		return start, numCommits, nil
+	}
+
+	return 0, 0, fmt.Errorf("%v was not found in the history of %q", observed, repoDir)
+}
+
+// extractCommit writes the content of the repository at commit to targetDir.
+func extractCommit(ctx context.Context, repoDir, commit, targetDir string) error {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "git", "-C", repoDir, "archive", "--format=tar", commit)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to archive %v: %w stderr: %v", commit, err, stderr.String())
+	}
+
+	tarReader := tar.NewReader(stdout)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed reading archive for %v: %w", commit, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		target := filepath.Join(targetDir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(targetDir)+string(os.PathSeparator)) {
+			return fmt.Errorf("archive entry %q is outside of %q", header.Name, targetDir)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("unable to create dir for %q: %w", target, err)
+		}
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return fmt.Errorf("failed reading %q from archive: %w", header.Name, err)
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return fmt.Errorf("unable to write %q: %w", target, err)
+		}
+	}
+}
+
+func git(ctx context.Context, repoDir string, args ...string) (string, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repoDir}, args...)...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed running git %v: %w stderr: %v", strings.Join(args, " "), err, stderr.String())
+	}
+	return stdout.String(), nil
+}
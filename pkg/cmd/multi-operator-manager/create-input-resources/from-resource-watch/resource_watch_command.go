@@ -0,0 +1,188 @@
+package from_resource_watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift/multi-operator-manager/pkg/applyconfiguration"
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryapplyconfiguration"
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryinputresources"
+	"github.com/openshift/multi-operator-manager/pkg/test/testapplyconfiguration"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"sigs.k8s.io/yaml"
+)
+
+type FromResourceWatchFlags struct {
+	// ResourceWatchRepository is a local clone of a resource-watch git repository
+	ResourceWatchRepository string
+	// ApplyConfigurationOutputDirectory is the output-dir of the failing apply-configuration run
+	ApplyConfigurationOutputDirectory string
+
+	// OutputDirectory is the test directory to create.  It will contain test.yaml and input-dir
+	OutputDirectory string
+	TestName        string
+	// BinaryName is written to test.yaml, it is the path to the operator binary relative to where tests are run.
+	BinaryName string
+
+	InputResourcesFile string
+	OperatorBinary     string
+
+	Streams genericiooptions.IOStreams
+}
+
+func NewCreateInputResourcesFromResourceWatchFlags(streams genericiooptions.IOStreams) *FromResourceWatchFlags {
+	return &FromResourceWatchFlags{
+		Streams: streams,
+	}
+}
+
+func NewCreateInputResourcesFromResourceWatchCommand(streams genericiooptions.IOStreams) *cobra.Command {
+	f := NewCreateInputResourcesFromResourceWatchFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "from-resource-watch",
+		Short: "Find the state of the cluster seen by a failing apply-configuration run in a resource-watch git repository and write it as a test.",
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			o, err := f.ToOptions(ctx)
+			if err != nil {
+				return err
+			}
+			if err := o.Run(ctx); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *FromResourceWatchFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.ResourceWatchRepository, "resource-watch-repo", f.ResourceWatchRepository, "The local resource-watch git repository to search.")
+	flags.StringVar(&f.ApplyConfigurationOutputDirectory, "apply-configuration-output-dir", f.ApplyConfigurationOutputDirectory, "The output-dir of the failing apply-configuration run. The resourceVersions in its mutations select the commit.")
+	flags.StringVar(&f.OutputDirectory, "output-dir", f.OutputDirectory, "The test directory to create. test.yaml and input-dir are written here.")
+	flags.StringVar(&f.TestName, "test-name", f.TestName, "The name of the test written to test.yaml. Defaults to the base name of --output-dir.")
+	flags.StringVar(&f.BinaryName, "binary-name", f.BinaryName, "The operator binary written to test.yaml, relative to where tests are run. For instance, ./sample-operator.")
+	flags.StringVar(&f.InputResourcesFile, "input-resources", f.InputResourcesFile, "The file where pertinent resources are stored.")
+	flags.StringVar(&f.OperatorBinary, "operator-binary", f.OperatorBinary, "Path to the operator binary to call <operator-binary> input-resources.")
+}
+
+func (f *FromResourceWatchFlags) Validate() error {
+	if len(f.ResourceWatchRepository) == 0 {
+		return fmt.Errorf("--resource-watch-repo is required")
+	}
+	if len(f.ApplyConfigurationOutputDirectory) == 0 {
+		return fmt.Errorf("--apply-configuration-output-dir is required")
+	}
+	if len(f.OutputDirectory) == 0 {
+		return fmt.Errorf("--output-dir is required")
+	}
+	if len(f.BinaryName) == 0 {
+		return fmt.Errorf("--binary-name is required")
+	}
+	if (len(f.InputResourcesFile) == 0) == (len(f.OperatorBinary) == 0) {
+		return fmt.Errorf("exactly one of --input-resources and --operator-binary is required")
+	}
+	return nil
+}
+
+func (f *FromResourceWatchFlags) ToOptions(ctx context.Context) (*FromResourceWatchOptions, error) {
+	inputResources, err := applyconfiguration.LoadInputResources(ctx, f.InputResourcesFile, f.OperatorBinary)
+	if err != nil {
+		return nil, err
+	}
+
+	// a failing run can have validation errors, but we still want the mutations it produced.
+	applyConfigurationResult, err := libraryapplyconfiguration.NewApplyConfigurationResultFromDirectory(os.DirFS(f.ApplyConfigurationOutputDirectory), f.ApplyConfigurationOutputDirectory, nil)
+	if applyConfigurationResult == nil {
+		return nil, fmt.Errorf("unable to read apply-configuration output %q: %w", f.ApplyConfigurationOutputDirectory, err)
+	}
+
+	testName := f.TestName
+	if len(testName) == 0 {
+		testName = filepath.Base(f.OutputDirectory)
+	}
+
+	return &FromResourceWatchOptions{
+		ResourceWatchRepository:  f.ResourceWatchRepository,
+		ApplyConfigurationResult: applyConfigurationResult,
+		InputResources:           inputResources,
+		OutputDirectory:          f.OutputDirectory,
+		TestName:                 testName,
+		BinaryName:               f.BinaryName,
+		Streams:                  f.Streams,
+	}, nil
+}
+
+type FromResourceWatchOptions struct {
+	ResourceWatchRepository  string
+	ApplyConfigurationResult libraryapplyconfiguration.AllDesiredMutationsGetter
+	InputResources           *libraryinputresources.InputResources
+
+	OutputDirectory string
+	TestName        string
+	BinaryName      string
+
+	Streams genericiooptions.IOStreams
+}
+
+func (o *FromResourceWatchOptions) Run(ctx context.Context) error {
+	observed, err := observedResourceVersionsFromMutations(o.ApplyConfigurationResult)
+	if err != nil {
+		return err
+	}
+	commit, err := findMatchingCommit(ctx, o.ResourceWatchRepository, observed)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Streams.Out, "found matching state at commit %v (%v)\n", commit.Hash, commit.Time.Format(time.RFC3339))
+
+	checkoutDir, err := os.MkdirTemp("", "resource-watch-")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(checkoutDir)
+	if err := extractCommit(ctx, o.ResourceWatchRepository, commit.Hash, checkoutDir); err != nil {
+		return err
+	}
+
+	// apply-configuration only reads the applyConfigurationResources, so operandResources are not part of a test.
+	inputDir := filepath.Join(o.OutputDirectory, "input-dir")
+	if err := libraryinputresources.WriteRequiredInputResourcesFromMustGather(ctx, o.InputResources, checkoutDir, inputDir); err != nil {
+		return err
+	}
+
+	testDescription := testapplyconfiguration.TestDescription{
+		BinaryName:  o.BinaryName,
+		TestName:    o.TestName,
+		Description: fmt.Sprintf("Created from resource-watch commit %v.", commit.Hash),
+		TestType:    testapplyconfiguration.TestTypeApplyConfiguration,
+		Now:         metav1.NewTime(commit.Time),
+	}
+	testDescriptionBytes, err := yaml.Marshal(testDescription)
+	if err != nil {
+		return fmt.Errorf("unable to serialize test.yaml: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(o.OutputDirectory, "test.yaml"), testDescriptionBytes, 0644); err != nil {
+		return fmt.Errorf("unable to write test.yaml: %w", err)
+	}
+
+	return nil
+}
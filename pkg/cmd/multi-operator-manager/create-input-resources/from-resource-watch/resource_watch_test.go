@@ -0,0 +1,197 @@
+package from_resource_watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/manifestclient"
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryapplyconfiguration"
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryinputresources"
+	"github.com/openshift/multi-operator-manager/pkg/test/testapplyconfiguration"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFromResourceWatch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is required")
+	}
+	ctx := context.Background()
+
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "", "init", "-q")
+	// secret a: rv 1 at commit 0, rv 2 at commit 1, rv 3 at commit 3
+	// configmap b: rv 10 at commit 0, rv 11 at commit 2
+	writeResourceWatchObject(t, repoDir, "v1", "Secret", "secrets", "a", "1")
+	writeResourceWatchObject(t, repoDir, "v1", "ConfigMap", "configmaps", "b", "10")
+	runGit(t, repoDir, "2024-01-01T00:00:00Z", "add", "-A")
+	runGit(t, repoDir, "2024-01-01T00:00:00Z", "commit", "-q", "-m", "0")
+	writeResourceWatchObject(t, repoDir, "v1", "Secret", "secrets", "a", "2")
+	runGit(t, repoDir, "2024-01-01T00:01:00Z", "commit", "-q", "-am", "1")
+	writeResourceWatchObject(t, repoDir, "v1", "ConfigMap", "configmaps", "b", "11")
+	runGit(t, repoDir, "2024-01-01T00:02:00Z", "commit", "-q", "-am", "2")
+	writeResourceWatchObject(t, repoDir, "v1", "Secret", "secrets", "a", "3")
+	runGit(t, repoDir, "2024-01-01T00:03:00Z", "commit", "-q", "-am", "3")
+
+	inputResources := &libraryinputresources.InputResources{
+		ApplyConfigurationResources: libraryinputresources.ResourceList{
+			ExactResources: []libraryinputresources.ExactResourceID{
+				libraryinputresources.ExactSecret("foo", "a"),
+				libraryinputresources.ExactConfigMap("foo", "b"),
+			},
+		},
+	}
+
+	testCases := []struct {
+		name                     string
+		secretResourceVersion    string
+		configMapResourceVersion string
+		expectedError            string
+		expectedNow              string
+	}{
+		{
+			name:                     "overlapping",
+			secretResourceVersion:    "2",
+			configMapResourceVersion: "10",
+			expectedNow:              "2024-01-01T00:01:00Z",
+		},
+		{
+			name:                     "latest",
+			secretResourceVersion:    "3",
+			configMapResourceVersion: "11",
+			expectedNow:              "2024-01-01T00:03:00Z",
+		},
+		{
+			name:                     "no-overlap",
+			secretResourceVersion:    "3",
+			configMapResourceVersion: "10",
+			expectedError:            "no single commit",
+		},
+		{
+			name:                     "missing",
+			secretResourceVersion:    "4",
+			configMapResourceVersion: "10",
+			expectedError:            "secrets.v1./a[foo]@4 was not found",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mutations := manifestclient.NewAllActionsTracker[manifestclient.TrackedSerializedRequest]()
+			mutations.AddRequest(newUpdateRequest(1, "secrets", "Secret", "a", tc.secretResourceVersion))
+			mutations.AddRequest(newUpdateRequest(2, "configmaps", "ConfigMap", "b", tc.configMapResourceVersion))
+
+			outputDir := t.TempDir()
+			o := &FromResourceWatchOptions{
+				ResourceWatchRepository:  repoDir,
+				ApplyConfigurationResult: libraryapplyconfiguration.NewApplyConfigurationFromClient(mutations),
+				InputResources:           inputResources,
+				OutputDirectory:          outputDir,
+				TestName:                 tc.name,
+				BinaryName:               "./sample-operator",
+				Streams:                  genericiooptions.NewTestIOStreamsDiscard(),
+			}
+			err := o.Run(ctx)
+			switch {
+			case len(tc.expectedError) == 0 && err != nil:
+				t.Fatal(err)
+			case len(tc.expectedError) > 0 && err == nil:
+				t.Fatalf("expected error containing %q, got nil", tc.expectedError)
+			case len(tc.expectedError) > 0 && !strings.Contains(err.Error(), tc.expectedError):
+				t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+			case len(tc.expectedError) > 0:
+				return
+			}
+
+			testDescriptionBytes, err := os.ReadFile(filepath.Join(outputDir, "test.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			testDescription := &testapplyconfiguration.TestDescription{}
+			if err := yaml.Unmarshal(testDescriptionBytes, testDescription); err != nil {
+				t.Fatal(err)
+			}
+			if testDescription.BinaryName != "./sample-operator" {
+				t.Errorf("expected binaryName ./sample-operator, got %q", testDescription.BinaryName)
+			}
+			if actual := testDescription.Now.UTC().Format("2006-01-02T15:04:05Z"); actual != tc.expectedNow {
+				t.Errorf("expected now %v, got %v", tc.expectedNow, actual)
+			}
+
+			actualResources, warnings, err := libraryinputresources.LenientResourcesFromDirRecursive(filepath.Join(outputDir, "input-dir"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(warnings) > 0 {
+				t.Fatalf("unexpected warnings: %v", warnings)
+			}
+			actualResourceVersions := map[string]string{}
+			for _, curr := range actualResources {
+				actualResourceVersions[curr.Content.GetName()] = curr.Content.GetResourceVersion()
+			}
+			if actualResourceVersions["a"] != tc.secretResourceVersion || actualResourceVersions["b"] != tc.configMapResourceVersion {
+				t.Errorf("expected a@%v and b@%v, got %v", tc.secretResourceVersion, tc.configMapResourceVersion, actualResourceVersions)
+			}
+		})
+	}
+}
+
+func TestFromResourceWatchFlagsValidate(t *testing.T) {
+	f := NewCreateInputResourcesFromResourceWatchFlags(genericiooptions.NewTestIOStreamsDiscard())
+	f.ResourceWatchRepository = "resource-watch"
+	f.ApplyConfigurationOutputDirectory = "apply-configuration-output"
+	f.OutputDirectory = "test"
+	f.InputResourcesFile = "input-resources.yaml"
+	if err := f.Validate(); err == nil || err.Error() != "--binary-name is required" {
+		t.Errorf("expected --binary-name to be required, got %v", err)
+	}
+
+	f.BinaryName = "./sample-operator"
+	if err := f.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func newUpdateRequest(requestNumber int, resource, kind, name, resourceVersion string) manifestclient.TrackedSerializedRequest {
+	return manifestclient.TrackedSerializedRequest{
+		RequestNumber: requestNumber,
+		SerializedRequest: manifestclient.SerializedRequest{
+			ActionMetadata: manifestclient.ActionMetadata{
+				Action: manifestclient.ActionUpdate,
+				ResourceMetadata: manifestclient.ResourceMetadata{
+					ResourceType: schema.GroupVersionResource{Version: "v1", Resource: resource},
+					Namespace:    "foo",
+					Name:         name,
+				},
+			},
+			KindType: schema.GroupVersionKind{Version: "v1", Kind: kind},
+			Body:     []byte(fmt.Sprintf("apiVersion: v1\nkind: %s\nmetadata:\n  namespace: foo\n  name: %s\n  resourceVersion: %q\n", kind, name, resourceVersion)),
+		},
+	}
+}
+
+func writeResourceWatchObject(t *testing.T, repoDir, apiVersion, kind, resource, name, resourceVersion string) {
+	t.Helper()
+	dir := filepath.Join(repoDir, "namespaces", "foo", "core", resource)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := fmt.Sprintf("apiVersion: %s\nkind: %s\nmetadata:\n  namespace: foo\n  name: %s\n  resourceVersion: %q\n", apiVersion, kind, name, resourceVersion)
+	if err := os.WriteFile(filepath.Join(dir, name+".yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runGit(t *testing.T, repoDir, date string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", repoDir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, output)
+	}
+}
@@ -0,0 +1,114 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/multi-operator-manager/pkg/applyconfiguration"
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryinputresources"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+type InputsDiffFlags struct {
+	LHSMustGatherDirectory string
+	RHSMustGatherDirectory string
+
+	InputResourcesFile string
+	OperatorBinary     string
+
+	// FailOnDifference returns an error if any input resource differs.
+	FailOnDifference bool
+
+	Streams genericiooptions.IOStreams
+}
+
+func NewInputsDiffFlags(streams genericiooptions.IOStreams) *InputsDiffFlags {
+	return &InputsDiffFlags{
+		Streams: streams,
+	}
+}
+
+func NewInputsDiffCommand(streams genericiooptions.IOStreams) *cobra.Command {
+	f := NewInputsDiffFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare two must-gathers, considering only the resources an operator reads.",
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			if err := f.Run(ctx); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *InputsDiffFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.LHSMustGatherDirectory, "lhs-must-gather-dir", f.LHSMustGatherDirectory, "The directory where the first must-gather is located. A .tar, .tar.gz, or .tgz must-gather archive is also accepted.")
+	flags.StringVar(&f.RHSMustGatherDirectory, "rhs-must-gather-dir", f.RHSMustGatherDirectory, "The directory where the second must-gather is located. A .tar, .tar.gz, or .tgz must-gather archive is also accepted.")
+	flags.StringVar(&f.InputResourcesFile, "input-resources", f.InputResourcesFile, "The file where pertinent resources are stored.")
+	flags.StringVar(&f.OperatorBinary, "operator-binary", f.OperatorBinary, "Path to the operator binary to call <operator-binary> input-resources.")
+	flags.BoolVar(&f.FailOnDifference, "fail-on-difference", f.FailOnDifference, "Fail if any input resource differs between the must-gathers.")
+}
+
+func (f *InputsDiffFlags) Validate() error {
+	if len(f.LHSMustGatherDirectory) == 0 {
+		return fmt.Errorf("--lhs-must-gather-dir is required")
+	}
+	if len(f.RHSMustGatherDirectory) == 0 {
+		return fmt.Errorf("--rhs-must-gather-dir is required")
+	}
+	if (len(f.InputResourcesFile) == 0) == (len(f.OperatorBinary) == 0) {
+		return fmt.Errorf("exactly one of --input-resources and --operator-binary is required")
+	}
+	return nil
+}
+
+func (f *InputsDiffFlags) Run(ctx context.Context) error {
+	pertinentResources, err := applyconfiguration.LoadInputResources(ctx, f.InputResourcesFile, f.OperatorBinary)
+	if err != nil {
+		return err
+	}
+
+	lhsDynamicClient, err := libraryinputresources.NewDynamicClientFromMustGather(f.LHSMustGatherDirectory)
+	if err != nil {
+		return err
+	}
+	rhsDynamicClient, err := libraryinputresources.NewDynamicClientFromMustGather(f.RHSMustGatherDirectory)
+	if err != nil {
+		return err
+	}
+	differences, err := libraryinputresources.DiffRequiredInputResources(ctx, pertinentResources, lhsDynamicClient, rhsDynamicClient)
+	if err != nil {
+		return err
+	}
+
+	for _, difference := range differences {
+		fmt.Fprintln(f.Streams.Out, difference.String())
+	}
+	switch {
+	case len(differences) == 0:
+		fmt.Fprintln(f.Streams.ErrOut, "no input resources differ")
+	case f.FailOnDifference:
+		return fmt.Errorf("%d input resources differ between %q and %q", len(differences), f.LHSMustGatherDirectory, f.RHSMustGatherDirectory)
+	default:
+		fmt.Fprintf(f.Streams.ErrOut, "%d input resources differ between %q and %q\n", len(differences), f.LHSMustGatherDirectory, f.RHSMustGatherDirectory)
+	}
+
+	return nil
+}
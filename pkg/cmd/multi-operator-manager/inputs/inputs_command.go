@@ -0,0 +1,18 @@
+package inputs
+
+import (
+	"github.com/openshift/multi-operator-manager/pkg/cmd/multi-operator-manager/inputs/diff"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+func NewInputsCommand(streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "inputs",
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(
+		diff.NewInputsDiffCommand(streams),
+	)
+	return cmd
+}
@@ -2,6 +2,7 @@ package multi_operator_manager
 
 import (
 	create_input_resources "github.com/openshift/multi-operator-manager/pkg/cmd/multi-operator-manager/create-input-resources"
+	"github.com/openshift/multi-operator-manager/pkg/cmd/multi-operator-manager/inputs"
 	sample_operator "github.com/openshift/multi-operator-manager/pkg/cmd/multi-operator-manager/sample-operator"
 	"github.com/openshift/multi-operator-manager/pkg/cmd/multi-operator-manager/test"
 	"github.com/spf13/cobra"
@@ -27,6 +28,7 @@ func NewMultiOperatorManagerCommand(streams genericiooptions.IOStreams) *cobra.C
 		test.NewTestCommand(streams),
 		sample_operator.NewSampleOperatorCommand(streams),
 		create_input_resources.NewCreateInputResourcesCommand(streams),
+		inputs.NewInputsCommand(streams),
 	)
 
 	verflag.AddFlags(cmd.Flags())
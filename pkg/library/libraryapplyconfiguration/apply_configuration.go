@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/openshift/multi-operator-manager/pkg/library/libraryoutputresources"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/openshift/library-go/pkg/manifestclient"
@@ -46,6 +47,14 @@ func UnspecifiedOutputResources(allDesiredMutationsGetter AllDesiredMutationsGet
 		}
 	}
 
+	combinedList := combinedOutputResourceList(allAllowedOutputResources)
+	filteredMutationRequests := FilterSerializedRequests(allMutationRequests, combinedList)
+
+	return manifestclient.DifferenceOfSerializedRequests(allMutationRequests, filteredMutationRequests)
+}
+
+// combinedOutputResourceList merges the lists of every cluster type, for checks that do not depend on the cluster type.
+func combinedOutputResourceList(allAllowedOutputResources *libraryoutputresources.OutputResources) *libraryoutputresources.ResourceList {
 	combinedList := &libraryoutputresources.ResourceList{}
 	combinedList.ExactResources = append(combinedList.ExactResources, allAllowedOutputResources.ConfigurationResources.ExactResources...)
 	combinedList.ExactResources = append(combinedList.ExactResources, allAllowedOutputResources.ManagementResources.ExactResources...)
@@ -56,9 +65,25 @@ func UnspecifiedOutputResources(allDesiredMutationsGetter AllDesiredMutationsGet
 	combinedList.EventingNamespaces = append(combinedList.EventingNamespaces, allAllowedOutputResources.ConfigurationResources.EventingNamespaces...)
 	combinedList.EventingNamespaces = append(combinedList.EventingNamespaces, allAllowedOutputResources.ManagementResources.EventingNamespaces...)
 	combinedList.EventingNamespaces = append(combinedList.EventingNamespaces, allAllowedOutputResources.UserWorkloadResources.EventingNamespaces...)
-	filteredMutationRequests := FilterSerializedRequests(allMutationRequests, combinedList)
+	combinedList.LabelSelectedResources = append(combinedList.LabelSelectedResources, allAllowedOutputResources.ConfigurationResources.LabelSelectedResources...)
+	combinedList.LabelSelectedResources = append(combinedList.LabelSelectedResources, allAllowedOutputResources.ManagementResources.LabelSelectedResources...)
+	combinedList.LabelSelectedResources = append(combinedList.LabelSelectedResources, allAllowedOutputResources.UserWorkloadResources.LabelSelectedResources...)
 
-	return manifestclient.DifferenceOfSerializedRequests(allMutationRequests, filteredMutationRequests)
+	return combinedList
+}
+
+// withoutActionRestrictions returns a copy of resourceList that allows every action on the declared resources.
+func withoutActionRestrictions(resourceList *libraryoutputresources.ResourceList) *libraryoutputresources.ResourceList {
+	ret := *resourceList
+	ret.ExactResources = slices.Clone(resourceList.ExactResources)
+	ret.GeneratedNameResources = slices.Clone(resourceList.GeneratedNameResources)
+	for i := range ret.ExactResources {
+		ret.ExactResources[i].Actions = nil
+	}
+	for i := range ret.GeneratedNameResources {
+		ret.GeneratedNameResources[i].Actions = nil
+	}
+	return &ret
 }
 
 func ValidateAllDesiredMutationsGetter(allDesiredMutationsGetter AllDesiredMutationsGetter, allAllowedOutputResources *libraryoutputresources.OutputResources) error {
@@ -69,13 +94,23 @@ func ValidateAllDesiredMutationsGetter(allDesiredMutationsGetter AllDesiredMutat
 	}
 
 	unspecifiedOutputResources := UnspecifiedOutputResources(allDesiredMutationsGetter, allAllowedOutputResources)
-	if len(unspecifiedOutputResources) > 0 {
+	// resources that are declared, but only for other actions, are reported separately so the fix is clear.
+	declaredWithOtherActions := FilterSerializedRequests(unspecifiedOutputResources, withoutActionRestrictions(combinedOutputResourceList(allAllowedOutputResources)))
+	undeclaredOutputResources := manifestclient.DifferenceOfSerializedRequests(unspecifiedOutputResources, declaredWithOtherActions)
+	if len(undeclaredOutputResources) > 0 {
 		unspecifiedOutputIdentifiers := []string{}
-		for _, curr := range unspecifiedOutputResources {
+		for _, curr := range undeclaredOutputResources {
 			unspecifiedOutputIdentifiers = append(unspecifiedOutputIdentifiers, curr.GetSerializedRequest().StringID())
 		}
 		errs = append(errs, fmt.Errorf("%d output-resource were produced, but not present in the specified output: %v", len(unspecifiedOutputIdentifiers), strings.Join(unspecifiedOutputIdentifiers, ", ")))
 	}
+	if len(declaredWithOtherActions) > 0 {
+		disallowedActionIdentifiers := []string{}
+		for _, curr := range declaredWithOtherActions {
+			disallowedActionIdentifiers = append(disallowedActionIdentifiers, curr.GetSerializedRequest().StringID())
+		}
+		errs = append(errs, fmt.Errorf("%d output-resource were produced with actions that are not allowed by the specified output: %v", len(disallowedActionIdentifiers), strings.Join(disallowedActionIdentifiers, ", ")))
+	}
 
 	return errors.Join(errs...)
 }
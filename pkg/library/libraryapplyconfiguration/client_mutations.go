@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"github.com/openshift/library-go/pkg/manifestclient"
 	"github.com/openshift/multi-operator-manager/pkg/library/libraryoutputresources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
 )
 
 type clientBasedClusterApplyResult struct {
@@ -128,8 +132,7 @@ func FilterSerializedRequests(requests []manifestclient.SerializedRequestish, al
 	filteredRequests := []manifestclient.SerializedRequestish{}
 
 	for _, curr := range requests {
-		metadata := curr.GetSerializedRequest().GetLookupMetadata()
-		if metadataMatchesFilter(metadata, allowedResources) {
+		if metadataMatchesFilter(curr.GetSerializedRequest(), allowedResources) {
 			filteredRequests = append(filteredRequests, curr)
 		}
 	}
@@ -147,10 +150,11 @@ var (
 	}
 )
 
-func metadataMatchesFilter(metadata manifestclient.ActionMetadata, allowedResources *libraryoutputresources.ResourceList) bool {
+func metadataMatchesFilter(request *manifestclient.SerializedRequest, allowedResources *libraryoutputresources.ResourceList) bool {
 	if allowedResources == nil {
 		return true
 	}
+	metadata := request.GetLookupMetadata()
 
 	gr := metadata.ResourceType.GroupResource()
 	if gr == coreEventGR || gr == eventGR {
@@ -168,7 +172,8 @@ func metadataMatchesFilter(metadata manifestclient.ActionMetadata, allowedResour
 		if metadata.ResourceType.Group == curr.Group &&
 			metadata.ResourceType.Resource == curr.Resource &&
 			metadata.Namespace == curr.Namespace &&
-			metadata.Name == curr.Name {
+			metadata.Name == curr.Name &&
+			libraryoutputresources.AllowsAction(curr.Actions, metadata.Action) {
 			return true
 		}
 	}
@@ -179,10 +184,47 @@ func metadataMatchesFilter(metadata manifestclient.ActionMetadata, allowedResour
 		if metadata.ResourceType.Group == curr.Group &&
 			metadata.ResourceType.Resource == curr.Resource &&
 			metadata.Namespace == curr.Namespace &&
-			metadata.GenerateName == curr.GeneratedName {
+			metadata.GenerateName == curr.GeneratedName &&
+			libraryoutputresources.AllowsAction(curr.Actions, metadata.Action) {
 			return true
 		}
 	}
+	if len(allowedResources.LabelSelectedResources) > 0 && labelSelectedActions.Has(metadata.Action) {
+		bodyLabels := labelsFromBody(request.Body)
+		for _, curr := range allowedResources.LabelSelectedResources {
+			if metadata.ResourceType.Group != curr.Group ||
+				metadata.ResourceType.Resource != curr.Resource ||
+				metadata.Namespace != curr.Namespace {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(&curr.LabelSelector)
+			if err != nil || selector.Empty() {
+				continue
+			}
+			if bodyLabels != nil && selector.Matches(bodyLabels) {
+				return true
+			}
+		}
+	}
 
 	return false
 }
+
+// labelSelectedActions are the actions whose body is the whole object, so its labels are the labels of the result.
+// A patch that only sets labels would otherwise let an operator claim any object by adding the selected labels.
+var labelSelectedActions = sets.New(manifestclient.ActionApply, manifestclient.ActionCreate, manifestclient.ActionUpdate)
+
+// labelsFromBody returns the labels of the object in a mutation body, or nil if the body is not an object with labels.
+func labelsFromBody(body []byte) labels.Set {
+	if len(body) == 0 {
+		return nil
+	}
+	obj := &metav1.PartialObjectMetadata{}
+	if err := yaml.Unmarshal(body, obj); err != nil {
+		return nil
+	}
+	if len(obj.Labels) == 0 {
+		return nil
+	}
+	return obj.Labels
+}
@@ -3,7 +3,9 @@ package libraryapplyconfiguration
 import (
 	"github.com/openshift/library-go/pkg/manifestclient"
 	"github.com/openshift/multi-operator-manager/pkg/library/libraryoutputresources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"strings"
 	"testing"
 )
 
@@ -86,3 +88,158 @@ func TestNewApplyConfigurationFromClient(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterSerializedRequestsLabelSelected(t *testing.T) {
+	newRequest := func(action manifestclient.Action, namespace, name, body string) manifestclient.SerializedRequestish {
+		return manifestclient.SerializedRequest{
+			ActionMetadata: manifestclient.ActionMetadata{
+				Action: action,
+				ResourceMetadata: manifestclient.ResourceMetadata{
+					ResourceType: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+					Namespace:    namespace,
+					Name:         name,
+				},
+			},
+			KindType: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Body:     []byte(body),
+		}
+	}
+	revisionedBody := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config-3","namespace":"openshift-example","labels":{"revision":"3"}}}`
+	otherBody := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"openshift-example","labels":{"app":"example"}}}`
+
+	allowedResources := &libraryoutputresources.ResourceList{
+		LabelSelectedResources: []libraryoutputresources.LabelSelectedResource{
+			{
+				OutputResourceTypeIdentifier: libraryoutputresources.OutputResourceTypeIdentifier{Version: "v1", Resource: "configmaps"},
+				Namespace:                    "openshift-example",
+				LabelSelector: metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "revision", Operator: metav1.LabelSelectorOpExists}},
+				},
+			},
+			{
+				// an empty selector never matches
+				OutputResourceTypeIdentifier: libraryoutputresources.OutputResourceTypeIdentifier{Version: "v1", Resource: "configmaps"},
+				Namespace:                    "openshift-example",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		request  manifestclient.SerializedRequestish
+		expected bool
+	}{
+		{
+			name:     "create with matching labels",
+			request:  newRequest(manifestclient.ActionCreate, "openshift-example", "config-3", revisionedBody),
+			expected: true,
+		},
+		{
+			name:     "apply with matching labels as yaml",
+			request:  newRequest(manifestclient.ActionApply, "openshift-example", "config-3", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config-3\n  labels:\n    revision: \"3\"\n"),
+			expected: true,
+		},
+		{
+			name:     "labels do not match",
+			request:  newRequest(manifestclient.ActionUpdate, "openshift-example", "config", otherBody),
+			expected: false,
+		},
+		{
+			name:     "different namespace",
+			request:  newRequest(manifestclient.ActionCreate, "openshift-other", "config-3", revisionedBody),
+			expected: false,
+		},
+		{
+			name:     "delete has no labels",
+			request:  newRequest(manifestclient.ActionDelete, "openshift-example", "config-3", ""),
+			expected: false,
+		},
+		{
+			name:     "merge patch setting matching labels",
+			request:  newRequest(manifestclient.ActionPatch, "openshift-example", "config", `{"metadata":{"labels":{"revision":"3"}}}`),
+			expected: false,
+		},
+		{
+			name:     "json patch has no labels",
+			request:  newRequest(manifestclient.ActionPatch, "openshift-example", "config-3", `[{"op":"replace","path":"/data/foo","value":"bar"}]`),
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := FilterSerializedRequests([]manifestclient.SerializedRequestish{tt.request}, allowedResources)
+			if matched := len(actual) == 1; matched != tt.expected {
+				t.Errorf("expected match=%v, got %v", tt.expected, matched)
+			}
+		})
+	}
+}
+
+func TestValidateAllDesiredMutationsGetterActions(t *testing.T) {
+	newRequest := func(requestNumber int, action manifestclient.Action, gvr schema.GroupVersionResource, kind, namespace, name string) manifestclient.TrackedSerializedRequest {
+		return manifestclient.TrackedSerializedRequest{
+			RequestNumber: requestNumber,
+			SerializedRequest: manifestclient.SerializedRequest{
+				ActionMetadata: manifestclient.ActionMetadata{
+					Action: action,
+					ResourceMetadata: manifestclient.ResourceMetadata{
+						ResourceType: gvr,
+						Namespace:    namespace,
+						Name:         name,
+					},
+				},
+				KindType: gvr.GroupVersion().WithKind(kind),
+				Body:     []byte(""),
+			},
+		}
+	}
+	authenticationsGVR := schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "authentications"}
+	clusterOperatorsGVR := schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"}
+	secretsGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+	mutationTracker := manifestclient.NewAllActionsTracker[manifestclient.TrackedSerializedRequest]()
+	mutationTracker.AddRequest(newRequest(1, manifestclient.ActionApplyStatus, authenticationsGVR, "Authentication", "", "cluster"))
+	mutationTracker.AddRequest(newRequest(2, manifestclient.ActionApplyStatus, clusterOperatorsGVR, "ClusterOperator", "", "authentication"))
+	mutationTracker.AddRequest(newRequest(3, manifestclient.ActionDelete, clusterOperatorsGVR, "ClusterOperator", "", "authentication"))
+	mutationTracker.AddRequest(newRequest(4, manifestclient.ActionUpdate, authenticationsGVR, "Authentication", "", "cluster"))
+	mutationTracker.AddRequest(newRequest(5, manifestclient.ActionCreate, secretsGVR, "Secret", "foo", "bar"))
+
+	operatorResource := libraryoutputresources.ExactLowLevelOperator("authentications")
+	operatorResource.Actions = []manifestclient.Action{manifestclient.ActionApplyStatus}
+	clusterOperator := libraryoutputresources.ExactClusterOperator("authentication")
+	clusterOperator.Actions = []manifestclient.Action{manifestclient.ActionApply, manifestclient.ActionApplyStatus}
+	allowedOutputResources := &libraryoutputresources.OutputResources{
+		ManagementResources: libraryoutputresources.ResourceList{
+			ExactResources: []libraryoutputresources.ExactResourceID{operatorResource, clusterOperator},
+		},
+	}
+
+	applyConfiguration := NewApplyConfigurationFromClient(mutationTracker)
+	unspecified := UnspecifiedOutputResources(applyConfiguration, allowedOutputResources)
+	actualUnspecified := []string{}
+	for _, curr := range unspecified {
+		actualUnspecified = append(actualUnspecified, curr.GetSerializedRequest().StringID())
+	}
+	// every cluster type sees every request from the client, so each unspecified request is present once per cluster type.
+	if len(actualUnspecified) != 9 {
+		t.Errorf("expected 9 unspecified requests, got %v", actualUnspecified)
+	}
+
+	err := ValidateAllDesiredMutationsGetter(applyConfiguration, allowedOutputResources)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, expected := range []string{
+		"output-resource were produced, but not present in the specified output: Create-Secret.v1./bar[foo]",
+		"output-resource were produced with actions that are not allowed by the specified output: ",
+		"Delete-ClusterOperator.v1.config.openshift.io/authentication[]",
+		"Update-Authentication.v1.operator.openshift.io/cluster[]",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error containing %q, got %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), "ApplyStatus") {
+		t.Errorf("allowed actions were reported: %v", err)
+	}
+}
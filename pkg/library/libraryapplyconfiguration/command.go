@@ -8,6 +8,7 @@ import (
 
 	"github.com/openshift/library-go/pkg/manifestclient"
 	"github.com/openshift/multi-operator-manager/pkg/flagtypes"
+	"github.com/openshift/multi-operator-manager/pkg/library/librarymustgather"
 	"github.com/openshift/multi-operator-manager/pkg/library/libraryoutputresources"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -44,7 +45,7 @@ type applyConfigurationFlags struct {
 	applyConfigurationFn ApplyConfigurationFunc
 	outputResourcesFn    libraryoutputresources.OutputResourcesFunc
 
-	// InputDirectory is a directory that contains the must-gather formatted inputs
+	// InputDirectory is a directory or archive that contains the must-gather formatted inputs
 	inputDirectory string
 
 	// OutputDirectory is the directory to where output should be stored
@@ -106,7 +107,7 @@ func newApplyConfigurationCommand(applyConfigurationFn ApplyConfigurationFunc, o
 }
 
 func (f *applyConfigurationFlags) BindFlags(flags *pflag.FlagSet) {
-	flags.StringVar(&f.inputDirectory, "input-dir", f.inputDirectory, "The directory where the resource input is stored.")
+	flags.StringVar(&f.inputDirectory, "input-dir", f.inputDirectory, "The directory where the resource input is stored. A .tar, .tar.gz, or .tgz must-gather archive is also accepted.")
 	flags.StringVar(&f.outputDirectory, "output-dir", f.outputDirectory, "The directory where the output is stored.")
 	flags.StringSliceVar(&f.controllers, "controllers", []string{"*"}, "A list of controllers to enable. '*' enables all controllers, 'foo' enables the controller named 'foo', '-foo' disables the controller named 'foo'. Default: `*`")
 	nowFlag := flagtypes.NewTimeValue(f.now, &f.now, []string{time.RFC3339})
@@ -127,7 +128,11 @@ func (f *applyConfigurationFlags) Validate() error {
 }
 
 func (f *applyConfigurationFlags) ToOptions(ctx context.Context) (*applyConfigurationOptions, error) {
-	momClient := manifestclient.NewHTTPClient(f.inputDirectory)
+	inputFS, err := librarymustgather.NewFS(f.inputDirectory)
+	if err != nil {
+		return nil, err
+	}
+	momClient := NewReadTrackingClient(manifestclient.NewTestingHTTPClient(inputFS))
 	input := ApplyConfigurationInput{
 		MutationTrackingClient: momClient,
 		Clock:                  clocktesting.NewFakeClock(f.now),
@@ -17,6 +17,8 @@ type ApplyConfigurationResult interface {
 	Stdout() string
 	Stderr() string
 	ControllerResults() *ApplyConfigurationRunResult
+	// InputReads is nil when the output does not include input-reads.yaml.
+	InputReads() *ApplyConfigurationInputReads
 
 	AllDesiredMutationsGetter
 }
@@ -27,6 +29,7 @@ type simpleApplyConfigurationResult struct {
 	stdout            string
 	stderr            string
 	controllerResults *ApplyConfigurationRunResult
+	inputReads        *ApplyConfigurationInputReads
 
 	applyConfiguration *applyConfiguration
 }
@@ -84,6 +87,25 @@ func NewApplyConfigurationResultFromDirectory(inFS fs.FS, outputDirectory string
 		}
 	}
 
+	var inputReads *ApplyConfigurationInputReads
+	inputReadsLocation := filepath.Join(outputDirectory, "input-reads.yaml")
+	inputReadsContent, err := fs.ReadFile(inFS, "input-reads.yaml")
+	if err != nil && !os.IsNotExist(err) {
+		errs = append(errs, fmt.Errorf("failed reading %q: %w", inputReadsLocation, err))
+	}
+	if len(inputReadsContent) > 0 {
+		if asJSON, err := yaml.ToJSON(inputReadsContent); err != nil {
+			errs = append(errs, fmt.Errorf("unable to convert input-reads.yaml to json: %w", err))
+		} else {
+			localInputReads := &ApplyConfigurationInputReads{}
+			if err := json.Unmarshal(asJSON, localInputReads); err != nil {
+				errs = append(errs, fmt.Errorf("unable to parse input-reads.yaml: %w", err))
+			} else {
+				inputReads = localInputReads
+			}
+		}
+	}
+
 	outputContent, err := fs.ReadDir(inFS, ".")
 	switch {
 	case errors.Is(err, fs.ErrNotExist) && execError != nil:
@@ -110,6 +132,7 @@ func NewApplyConfigurationResultFromDirectory(inFS fs.FS, outputDirectory string
 		stdout:             string(stdoutContent),
 		stderr:             string(stderrContent),
 		controllerResults:  controllerResults,
+		inputReads:         inputReads,
 		outputDirectory:    outputDirectory,
 		applyConfiguration: &applyConfiguration{},
 	}
@@ -129,6 +152,9 @@ func NewApplyConfigurationResultFromDirectory(inFS fs.FS, outputDirectory string
 		if currContent.Name() == "controller-results.yaml" {
 			continue
 		}
+		if currContent.Name() == "input-reads.yaml" {
+			continue
+		}
 
 		if !currContent.IsDir() {
 			errs = append(errs, fmt.Errorf("unexpected file %q, only target cluster directories are: %v", filepath.Join(outputDirectory, currContent.Name()), sets.List(AllClusterTypes)))
@@ -172,6 +198,10 @@ func (s *simpleApplyConfigurationResult) ControllerResults() *ApplyConfiguration
 	return s.controllerResults
 }
 
+func (s *simpleApplyConfigurationResult) InputReads() *ApplyConfigurationInputReads {
+	return s.inputReads
+}
+
 func (s *simpleApplyConfigurationResult) OutputDirectory() (string, error) {
 	return s.outputDirectory, nil
 }
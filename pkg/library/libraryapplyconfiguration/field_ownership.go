@@ -0,0 +1,319 @@
+package libraryapplyconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/manifestclient"
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryoutputresources"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// CurrentObjectGetter returns the object that a mutation applies to, or nil if it does not exist.
+type CurrentObjectGetter func(ctx context.Context, metadata manifestclient.ResourceMetadata) (*unstructured.Unstructured, error)
+
+// NewCurrentObjectGetter reads current objects using dynamicClient.
+func NewCurrentObjectGetter(dynamicClient dynamic.Interface) CurrentObjectGetter {
+	return func(ctx context.Context, metadata manifestclient.ResourceMetadata) (*unstructured.Unstructured, error) {
+		ret, err := dynamicClient.Resource(metadata.ResourceType).Namespace(metadata.Namespace).Get(ctx, metadata.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return ret, err
+	}
+}
+
+// identityFields are always allowed, they are needed to identify the object being mutated.
+var identityFields = [][]string{
+	{"apiVersion"},
+	{"kind"},
+	{"metadata", "name"},
+	{"metadata", "namespace"},
+	{"metadata", "generateName"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "uid"},
+}
+
+// serverManagedFields are set by the server, so an Update that drops them does not change them.
+var serverManagedFields = [][]string{
+	{"metadata", "creationTimestamp"},
+	{"metadata", "deletionTimestamp"},
+	{"metadata", "deletionGracePeriodSeconds"},
+	{"metadata", "generation"},
+	{"metadata", "managedFields"},
+	{"metadata", "selfLink"},
+}
+
+// ValidateFieldOwnership returns an error listing every mutation that sets fields outside of the fields declared for
+// its output resource.  Apply and merge patch bodies set every field they contain and JSON patches set the path of
+// every operation.  Update bodies contain the whole object, so only the fields that differ from the object returned by
+// currentObjectGetter are checked.  If currentObjectGetter is nil, every field in an Update body is checked.
+// Create and Delete replace the whole object, use the declared actions to restrict them.
+// Like mutations with disallowed actions, a violation fails the apply-configuration run, so that an operator clobbering
+// fields owned by someone else is caught before it ships.
+func ValidateFieldOwnership(ctx context.Context, allDesiredMutationsGetter AllDesiredMutationsGetter, allAllowedOutputResources *libraryoutputresources.OutputResources, currentObjectGetter CurrentObjectGetter) error {
+	if allDesiredMutationsGetter == nil || allAllowedOutputResources == nil {
+		return nil
+	}
+	combinedList := combinedOutputResourceList(allAllowedOutputResources)
+
+	errs := []error{}
+	violations := sets.New[string]()
+	for _, clusterType := range sets.List(AllClusterTypes) {
+		desiredMutationsGetter := allDesiredMutationsGetter.MutationsForClusterType(clusterType)
+		if desiredMutationsGetter == nil {
+			continue
+		}
+		for _, curr := range desiredMutationsGetter.Requests().AllRequests() {
+			request := curr.GetSerializedRequest()
+			allowedFields := declaredFields(request.GetLookupMetadata(), combinedList)
+			if allowedFields == nil {
+				continue
+			}
+			changedFields, err := changedFieldPaths(ctx, request, currentObjectGetter)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed checking fields of %v: %w", request.StringID(), err))
+				continue
+			}
+
+			disallowedFields := []string{}
+			for _, changedField := range changedFields {
+				if !fieldAllowed(changedField, allowedFields) {
+					disallowedFields = append(disallowedFields, fieldPathString(changedField))
+				}
+			}
+			if len(disallowedFields) > 0 {
+				slices.Sort(disallowedFields)
+				violations.Insert(fmt.Sprintf("%v sets %v", request.StringID(), strings.Join(slices.Compact(disallowedFields), ", ")))
+			}
+		}
+	}
+	if len(violations) > 0 {
+		errs = append(errs, fmt.Errorf("%d output-resource were produced that set fields outside of the fields allowed by the specified output: %v", len(violations), strings.Join(sets.List(violations), "; ")))
+	}
+
+	return errors.Join(errs...)
+}
+
+// declaredFields returns the parsed fields allowed for the resource, or nil if every field is allowed.
+func declaredFields(metadata manifestclient.ActionMetadata, allowedResources *libraryoutputresources.ResourceList) [][]string {
+	fields := []string{}
+	for _, curr := range allowedResources.ExactResources {
+		if len(metadata.GenerateName) > 0 {
+			continue
+		}
+		if metadata.ResourceType.Group == curr.Group &&
+			metadata.ResourceType.Resource == curr.Resource &&
+			metadata.Namespace == curr.Namespace &&
+			metadata.Name == curr.Name {
+			if len(curr.Fields) == 0 {
+				return nil
+			}
+			fields = append(fields, curr.Fields...)
+		}
+	}
+	for _, curr := range allowedResources.GeneratedNameResources {
+		if len(metadata.Name) > 0 {
+			continue
+		}
+		if metadata.ResourceType.Group == curr.Group &&
+			metadata.ResourceType.Resource == curr.Resource &&
+			metadata.Namespace == curr.Namespace &&
+			metadata.GenerateName == curr.GeneratedName {
+			if len(curr.Fields) == 0 {
+				return nil
+			}
+			fields = append(fields, curr.Fields...)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	ret := slices.Clone(identityFields)
+	for _, curr := range fields {
+		// invalid fields are reported when validating the output resources, here they simply allow nothing.
+		if segments, err := libraryoutputresources.ParseOwnedFieldPath(curr); err == nil {
+			ret = append(ret, segments)
+		}
+	}
+	return ret
+}
+
+func fieldAllowed(fieldPath []string, allowedFields [][]string) bool {
+	for _, allowed := range allowedFields {
+		if len(allowed) <= len(fieldPath) && slices.Equal(allowed, fieldPath[:len(allowed)]) {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldPathString(fieldPath []string) string {
+	if len(fieldPath) == 0 {
+		return "."
+	}
+	return libraryoutputresources.FieldPathString(fieldPath)
+}
+
+func changedFieldPaths(ctx context.Context, request *manifestclient.SerializedRequest, currentObjectGetter CurrentObjectGetter) ([][]string, error) {
+	switch request.Action {
+	case manifestclient.ActionApply, manifestclient.ActionApplyStatus:
+		body := map[string]interface{}{}
+		if err := yaml.Unmarshal(request.Body, &body); err != nil {
+			return nil, fmt.Errorf("unable to decode body: %w", err)
+		}
+		return setFieldPaths(nil, body), nil
+
+	case manifestclient.ActionPatch, manifestclient.ActionPatchStatus:
+		if request.PatchType == string(types.JSONPatchType) {
+			return jsonPatchFieldPaths(request.Body)
+		}
+		body := map[string]interface{}{}
+		if err := yaml.Unmarshal(request.Body, &body); err != nil {
+			return nil, fmt.Errorf("unable to decode body: %w", err)
+		}
+		return setFieldPaths(nil, body), nil
+
+	case manifestclient.ActionUpdate, manifestclient.ActionUpdateStatus:
+		body := map[string]interface{}{}
+		if err := yaml.Unmarshal(request.Body, &body); err != nil {
+			return nil, fmt.Errorf("unable to decode body: %w", err)
+		}
+		current := map[string]interface{}{}
+		if currentObjectGetter != nil {
+			currentObj, err := currentObjectGetter(ctx, request.ResourceMetadata)
+			if err != nil {
+				return nil, fmt.Errorf("unable to get current object: %w", err)
+			}
+			if currentObj != nil {
+				// round trip the current object so that its values have the same types as the body.
+				currentJSON, err := json.Marshal(currentObj.Object)
+				if err != nil {
+					return nil, fmt.Errorf("unable to encode current object: %w", err)
+				}
+				if err := yaml.Unmarshal(currentJSON, &current); err != nil {
+					return nil, fmt.Errorf("unable to decode current object: %w", err)
+				}
+			}
+		}
+
+		ret := [][]string{}
+		for _, curr := range append(setFieldPaths(nil, body), setFieldPaths(nil, current)...) {
+			if fieldAllowed(curr, serverManagedFields) {
+				continue
+			}
+			// status is only changed by UpdateStatus and UpdateStatus only changes status.
+			if isStatus := len(curr) > 0 && curr[0] == "status"; isStatus != (request.Action == manifestclient.ActionUpdateStatus) {
+				continue
+			}
+			bodyValue, inBody := nestedValue(body, curr)
+			currentValue, inCurrent := nestedValue(current, curr)
+			if inBody == inCurrent && reflect.DeepEqual(bodyValue, currentValue) {
+				continue
+			}
+			ret = append(ret, curr)
+		}
+		return ret, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// setFieldPaths returns the path of every value in obj.  Lists are values, so they are owned as a whole.
+// Empty objects set nothing.  Strategic merge patch directives like $patch and $retainKeys change their parent.
+func setFieldPaths(prefix []string, obj map[string]interface{}) [][]string {
+	ret := [][]string{}
+	for key, value := range obj {
+		if strings.HasPrefix(key, "$") {
+			ret = append(ret, slices.Clone(prefix))
+			continue
+		}
+		fieldPath := append(slices.Clone(prefix), key)
+		if child, ok := value.(map[string]interface{}); ok {
+			ret = append(ret, setFieldPaths(fieldPath, child)...)
+			continue
+		}
+		ret = append(ret, fieldPath)
+	}
+	return ret
+}
+
+func nestedValue(obj map[string]interface{}, fieldPath []string) (interface{}, bool) {
+	var ret interface{} = obj
+	for _, key := range fieldPath {
+		curr, ok := ret.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if ret, ok = curr[key]; !ok {
+			return nil, false
+		}
+	}
+	return ret, true
+}
+
+// jsonPatchFieldPaths returns the field changed by every operation.  List indexes are dropped, because lists are
+// owned as a whole.
+func jsonPatchFieldPaths(body []byte) ([][]string, error) {
+	operations := []map[string]interface{}{}
+	if err := yaml.Unmarshal(body, &operations); err != nil {
+		return nil, fmt.Errorf("unable to decode JSONPatch body: %w", err)
+	}
+
+	ret := [][]string{}
+	for _, operation := range operations {
+		pointers := []string{}
+		switch operation["op"] {
+		case "test":
+		case "move":
+			pointers = append(pointers, fmt.Sprint(operation["from"]), fmt.Sprint(operation["path"]))
+		default:
+			pointers = append(pointers, fmt.Sprint(operation["path"]))
+		}
+		for _, pointer := range pointers {
+			ret = append(ret, jsonPointerFieldPath(pointer))
+		}
+	}
+	return ret, nil
+}
+
+func jsonPointerFieldPath(pointer string) []string {
+	ret := []string{}
+	if len(pointer) == 0 {
+		return ret
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if token == "-" || isListIndex(token) {
+			// everything below a list belongs to the list.
+			break
+		}
+		ret = append(ret, token)
+	}
+	return ret
+}
+
+func isListIndex(token string) bool {
+	if len(token) == 0 {
+		return false
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
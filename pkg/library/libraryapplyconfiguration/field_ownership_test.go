@@ -0,0 +1,135 @@
+package libraryapplyconfiguration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/manifestclient"
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryoutputresources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestValidateFieldOwnership(t *testing.T) {
+	ingressesGVR := schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "ingresses"}
+	newRequest := func(requestNumber int, action manifestclient.Action, name, patchType, body string) manifestclient.TrackedSerializedRequest {
+		return manifestclient.TrackedSerializedRequest{
+			RequestNumber: requestNumber,
+			SerializedRequest: manifestclient.SerializedRequest{
+				ActionMetadata: manifestclient.ActionMetadata{
+					Action: action,
+					ResourceMetadata: manifestclient.ResourceMetadata{
+						ResourceType: ingressesGVR,
+						Name:         name,
+					},
+					PatchType: patchType,
+				},
+				KindType: ingressesGVR.GroupVersion().WithKind("Ingress"),
+				Body:     []byte(body),
+			},
+		}
+	}
+	currentIngress := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "config.openshift.io/v1",
+		"kind":       "Ingress",
+		"metadata": map[string]interface{}{
+			"name":              "cluster",
+			"resourceVersion":   "5",
+			"creationTimestamp": "2024-01-01T00:00:00Z",
+		},
+		"spec": map[string]interface{}{
+			"domain": "apps.example.com",
+			"componentRoutes": []interface{}{
+				map[string]interface{}{"name": "console", "namespace": "openshift-console"},
+			},
+		},
+	}}
+	currentObjectGetter := func(ctx context.Context, metadata manifestclient.ResourceMetadata) (*unstructured.Unstructured, error) {
+		if metadata.Name != "cluster" {
+			return nil, nil
+		}
+		return currentIngress.DeepCopy(), nil
+	}
+
+	mutationTracker := manifestclient.NewAllActionsTracker[manifestclient.TrackedSerializedRequest]()
+	// allowed
+	mutationTracker.AddRequest(newRequest(1, manifestclient.ActionApply, "cluster", "", `
+apiVersion: config.openshift.io/v1
+kind: Ingress
+metadata:
+  name: cluster
+  annotations:
+    example.openshift.io/owner: authentication
+spec:
+  componentRoutes:
+  - name: oauth-openshift
+    namespace: openshift-authentication
+`))
+	mutationTracker.AddRequest(newRequest(2, manifestclient.ActionPatch, "cluster", string(types.JSONPatchType), `
+- op: add
+  path: /spec/componentRoutes/-
+  value:
+    name: oauth-openshift
+- op: test
+  path: /spec/domain
+  value: apps.example.com
+`))
+	mutationTracker.AddRequest(newRequest(3, manifestclient.ActionUpdate, "cluster", "", `
+apiVersion: config.openshift.io/v1
+kind: Ingress
+metadata:
+  name: cluster
+  resourceVersion: "5"
+spec:
+  domain: apps.example.com
+  componentRoutes: []
+`))
+	// not restricted
+	mutationTracker.AddRequest(newRequest(4, manifestclient.ActionApply, "other", "", `{"spec":{"domain":"apps.other.com"}}`))
+	// not allowed
+	mutationTracker.AddRequest(newRequest(5, manifestclient.ActionPatch, "cluster", string(types.MergePatchType), `{"metadata":{"labels":{"foo":"bar"}},"spec":{"domain":null}}`))
+	mutationTracker.AddRequest(newRequest(6, manifestclient.ActionPatch, "cluster", string(types.JSONPatchType), `[{"op":"move","from":"/spec/domain","path":"/spec/appsDomain"}]`))
+	mutationTracker.AddRequest(newRequest(7, manifestclient.ActionUpdateStatus, "cluster", "", `
+apiVersion: config.openshift.io/v1
+kind: Ingress
+metadata:
+  name: cluster
+spec:
+  domain: ignored.example.com
+status:
+  defaultPlacement: Workers
+`))
+
+	ingress := libraryoutputresources.ExactConfigResource("ingresses")
+	ingress.Fields = []string{".spec.componentRoutes", ".metadata.annotations['example.openshift.io/owner']"}
+	allowedOutputResources := &libraryoutputresources.OutputResources{
+		ConfigurationResources: libraryoutputresources.ResourceList{
+			ExactResources: []libraryoutputresources.ExactResourceID{
+				ingress,
+				libraryoutputresources.ExactResource("config.openshift.io", "v1", "ingresses", "", "other"),
+			},
+		},
+	}
+
+	err := ValidateFieldOwnership(context.Background(), NewApplyConfigurationFromClient(mutationTracker), allowedOutputResources, currentObjectGetter)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, expected := range []string{
+		"3 output-resource were produced that set fields outside of the fields allowed by the specified output: ",
+		"Patch-Ingress.v1.config.openshift.io/cluster[] sets .metadata.labels.foo, .spec.domain",
+		"Patch-Ingress.v1.config.openshift.io/cluster[] sets .spec.appsDomain, .spec.domain",
+		"UpdateStatus-Ingress.v1.config.openshift.io/cluster[] sets .status.defaultPlacement",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error containing %q, got %v", expected, err)
+		}
+	}
+	for _, unexpected := range []string{"Apply-", "Update-", "componentRoutes", "ignored"} {
+		if strings.Contains(err.Error(), unexpected) {
+			t.Errorf("expected error not containing %q, got %v", unexpected, err)
+		}
+	}
+}
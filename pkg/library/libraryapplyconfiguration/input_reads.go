@@ -0,0 +1,213 @@
+package libraryapplyconfiguration
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openshift/library-go/pkg/manifestclient"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// ApplyConfigurationInputReads lists every read made against the input-dir while running apply-configuration.
+type ApplyConfigurationInputReads struct {
+	ControllerReads []ControllerInputReads `json:"controllerReads"`
+}
+
+type ControllerInputReads struct {
+	// ControllerName is empty for reads that were not made by a controller, for instance when informers start.
+	ControllerName string      `json:"controllerName"`
+	Reads          []InputRead `json:"reads"`
+}
+
+type InputRead struct {
+	// Verb is get or list.  Watches are recorded as lists.
+	Verb      string `json:"verb"`
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	// Name is set for gets and for lists restricted to a single name by a field selector.
+	Name          string `json:"name,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+func (r InputRead) String() string {
+	ret := fmt.Sprintf("%s %s.%s.%s", r.Verb, r.Resource, r.Version, r.Group)
+	if len(r.Group) == 0 {
+		ret = fmt.Sprintf("%s %s.%s", r.Verb, r.Resource, r.Version)
+	}
+	if len(r.Namespace) > 0 {
+		ret += fmt.Sprintf(" namespace=%s", r.Namespace)
+	}
+	if len(r.Name) > 0 {
+		ret += fmt.Sprintf(" name=%s", r.Name)
+	}
+	if len(r.LabelSelector) > 0 {
+		ret += fmt.Sprintf(" labelSelector=%s", r.LabelSelector)
+	}
+	return ret
+}
+
+// ReadTrackingClient is a MutationTrackingClient that also records every GET and LIST, by controller instance name.
+type ReadTrackingClient interface {
+	manifestclient.MutationTrackingClient
+	GetReads() *ApplyConfigurationInputReads
+	// Unwrap returns the client that reads are delegated to, for reads that must not be recorded.
+	Unwrap() manifestclient.MutationTrackingClient
+}
+
+type readTrackingClient struct {
+	delegate   manifestclient.MutationTrackingClient
+	httpClient *http.Client
+
+	roundTripper *readTrackingRoundTripper
+}
+
+var (
+	_ ReadTrackingClient = &readTrackingClient{}
+)
+
+func NewReadTrackingClient(delegate manifestclient.MutationTrackingClient) ReadTrackingClient {
+	roundTripper := &readTrackingRoundTripper{
+		delegate: delegate.GetHTTPClient().Transport,
+		requestInfoResolver: &apirequest.RequestInfoFactory{
+			APIPrefixes:          sets.NewString("api", "apis"),
+			GrouplessAPIPrefixes: sets.NewString("api"),
+		},
+		readsByController: map[string]sets.Set[InputRead]{},
+	}
+	return &readTrackingClient{
+		delegate: delegate,
+		httpClient: &http.Client{
+			Transport: roundTripper,
+		},
+		roundTripper: roundTripper,
+	}
+}
+
+func (c *readTrackingClient) GetHTTPClient() *http.Client {
+	return c.httpClient
+}
+
+func (c *readTrackingClient) GetMutations() *manifestclient.AllActionsTracker[manifestclient.TrackedSerializedRequest] {
+	return c.delegate.GetMutations()
+}
+
+func (c *readTrackingClient) GetReads() *ApplyConfigurationInputReads {
+	return c.roundTripper.getReads()
+}
+
+func (c *readTrackingClient) Unwrap() manifestclient.MutationTrackingClient {
+	return c.delegate
+}
+
+type readTrackingRoundTripper struct {
+	delegate            http.RoundTripper
+	requestInfoResolver *apirequest.RequestInfoFactory
+
+	lock              sync.Mutex
+	readsByController map[string]sets.Set[InputRead]
+}
+
+func (rt *readTrackingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == "GET" || req.Method == "HEAD" {
+		if err := rt.recordRead(req); err != nil {
+			return nil, err
+		}
+	}
+	return rt.delegate.RoundTrip(req)
+}
+
+func (rt *readTrackingRoundTripper) recordRead(req *http.Request) error {
+	requestInfo, err := rt.requestInfoResolver.NewRequestInfo(req)
+	if err != nil {
+		return fmt.Errorf("failed reading requestInfo: %w", err)
+	}
+	if !requestInfo.IsResourceRequest {
+		// discovery
+		return nil
+	}
+
+	read := InputRead{
+		Verb:      requestInfo.Verb,
+		Group:     requestInfo.APIGroup,
+		Version:   requestInfo.APIVersion,
+		Resource:  requestInfo.Resource,
+		Namespace: requestInfo.Namespace,
+		Name:      requestInfo.Name,
+	}
+	if read.Verb == "watch" {
+		read.Verb = "list"
+	}
+	if labelSelectorString := req.URL.Query().Get("labelSelector"); len(labelSelectorString) > 0 {
+		labelSelector, err := labels.Parse(labelSelectorString)
+		if err != nil {
+			return fmt.Errorf("failed parsing labelSelector %q: %w", labelSelectorString, err)
+		}
+		read.LabelSelector = labelSelector.String()
+	}
+
+	controllerName := manifestclient.ControllerInstanceNameFromContext(req.Context())
+
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	if _, ok := rt.readsByController[controllerName]; !ok {
+		rt.readsByController[controllerName] = sets.New[InputRead]()
+	}
+	rt.readsByController[controllerName].Insert(read)
+	return nil
+}
+
+func (rt *readTrackingRoundTripper) getReads() *ApplyConfigurationInputReads {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+
+	ret := &ApplyConfigurationInputReads{}
+	for controllerName, reads := range rt.readsByController {
+		ret.ControllerReads = append(ret.ControllerReads, ControllerInputReads{
+			ControllerName: controllerName,
+			Reads:          reads.UnsortedList(),
+		})
+	}
+	CanonicalizeApplyConfigurationInputReads(ret)
+	return ret
+}
+
+func CanonicalizeApplyConfigurationInputReads(obj *ApplyConfigurationInputReads) {
+	if obj == nil {
+		return
+	}
+	for _, curr := range obj.ControllerReads {
+		slices.SortStableFunc(curr.Reads, sortInputRead)
+	}
+	slices.SortStableFunc(obj.ControllerReads, func(a, b ControllerInputReads) int {
+		return strings.Compare(a.ControllerName, b.ControllerName)
+	})
+}
+
+func sortInputRead(a, b InputRead) int {
+	if c := strings.Compare(a.Group, b.Group); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Resource, b.Resource); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Version, b.Version); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Namespace, b.Namespace); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Verb, b.Verb); c != 0 {
+		return c
+	}
+	return strings.Compare(a.LabelSelector, b.LabelSelector)
+}
@@ -0,0 +1,71 @@
+package libraryapplyconfiguration
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/openshift/library-go/pkg/manifestclient"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const configMapList = `apiVersion: v1
+kind: ConfigMapList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: foo
+    namespace: openshift-config
+    labels:
+      app: foo
+`
+
+func TestReadTrackingClient(t *testing.T) {
+	ctx := context.Background()
+	readTrackingClient := NewReadTrackingClient(manifestclient.NewTestingHTTPClient(fstest.MapFS{
+		"namespaces/openshift-config/core/configmaps.yaml": &fstest.MapFile{Data: []byte(configMapList)},
+	}))
+	kubeClient, err := kubernetes.NewForConfigAndClient(manifestclient.RecommendedRESTConfig(), readTrackingClient.GetHTTPClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := kubeClient.CoreV1().ConfigMaps("openshift-config").List(ctx, metav1.ListOptions{LabelSelector: "app=foo"}); err != nil {
+		t.Fatal(err)
+	}
+	controllerCtx := manifestclient.WithControllerInstanceNameFromContext(ctx, "foo-controller")
+	if _, err := kubeClient.CoreV1().ConfigMaps("openshift-config").Get(controllerCtx, "foo", metav1.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	// repeated reads are only recorded once
+	if _, err := kubeClient.CoreV1().ConfigMaps("openshift-config").Get(controllerCtx, "foo", metav1.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kubeClient.CoreV1().Secrets("openshift-config").Get(controllerCtx, "missing", metav1.GetOptions{}); err == nil {
+		t.Fatal("expected missing secret")
+	}
+
+	expected := &ApplyConfigurationInputReads{
+		ControllerReads: []ControllerInputReads{
+			{
+				ControllerName: "",
+				Reads: []InputRead{
+					{Verb: "list", Version: "v1", Resource: "configmaps", Namespace: "openshift-config", LabelSelector: "app=foo"},
+				},
+			},
+			{
+				ControllerName: "foo-controller",
+				Reads: []InputRead{
+					{Verb: "get", Version: "v1", Resource: "configmaps", Namespace: "openshift-config", Name: "foo"},
+					{Verb: "get", Version: "v1", Resource: "secrets", Namespace: "openshift-config", Name: "missing"},
+				},
+			},
+		},
+	}
+	if actual := readTrackingClient.GetReads(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("unexpected reads: %#v", actual)
+	}
+}
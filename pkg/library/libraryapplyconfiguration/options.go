@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/openshift/library-go/pkg/manifestclient"
+	"k8s.io/client-go/dynamic"
 	"os"
 	"path/filepath"
 	"sigs.k8s.io/yaml"
@@ -62,6 +63,19 @@ func (o *applyConfigurationOptions) Run(ctx context.Context) error {
 		errs = append(errs, err)
 	}
 
+	// current objects are read around the read tracking, the operator did not read them.
+	inputClient := o.input.MutationTrackingClient
+	if readTrackingClient, ok := inputClient.(ReadTrackingClient); ok {
+		inputClient = readTrackingClient.Unwrap()
+	}
+	if inputClient != nil {
+		if dynamicClient, err := dynamic.NewForConfigAndClient(manifestclient.RecommendedRESTConfig(), inputClient.GetHTTPClient()); err != nil {
+			errs = append(errs, fmt.Errorf("failed creating client for current objects: %w", err))
+		} else if err := ValidateFieldOwnership(ctx, filteredResult, allAllowedOutputResources, NewCurrentObjectGetter(dynamicClient)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if err := WriteApplyConfiguration(filteredResult, o.outputDirectory); err != nil {
 		errs = append(errs, err)
 	}
@@ -71,6 +85,16 @@ func (o *applyConfigurationOptions) Run(ctx context.Context) error {
 		}
 	}
 
+	if readTrackingClient, ok := o.input.MutationTrackingClient.(ReadTrackingClient); ok {
+		if inputReadsBytes, err := yaml.Marshal(readTrackingClient.GetReads()); err != nil {
+			errs = append(errs, fmt.Errorf("failed marshalling input reads: %w", err))
+		} else {
+			if err := os.WriteFile(filepath.Join(o.outputDirectory, "input-reads.yaml"), inputReadsBytes, 0644); err != nil {
+				errs = append(errs, fmt.Errorf("failed writing input reads: %w", err))
+			}
+		}
+	}
+
 	if controllerResultBytes, err := yaml.Marshal(controllerResults); err != nil {
 		errs = append(errs, fmt.Errorf("failed marshalling controller results: %w", err))
 	} else {
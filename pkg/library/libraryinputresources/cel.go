@@ -0,0 +1,110 @@
+package libraryinputresources
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// referenceCELVariableName is the name the referring resource is bound to in reference expressions.
+// This matches the convention used by kube for ValidatingAdmissionPolicy.
+const referenceCELVariableName = "object"
+
+// referenceCELCostLimit bounds the cost of evaluating one reference expression against one referring resource.
+// This matches the per call limit kube uses for ValidatingAdmissionPolicy.
+const referenceCELCostLimit = 1000000
+
+var referenceCELEnv *cel.Env
+
+// referenceCELPrograms holds the compiled program for every expression, so that a reference evaluated against many
+// referring resources is only compiled once.
+var referenceCELPrograms = struct {
+	sync.Mutex
+	programs map[string]cel.Program
+}{programs: map[string]cel.Program{}}
+
+func init() {
+	var err error
+	referenceCELEnv, err = cel.NewEnv(
+		cel.Variable(referenceCELVariableName, cel.DynType),
+		ext.Strings(),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("coding error: unable to build CEL environment: %v", err))
+	}
+}
+
+// compileReferenceExpression parses and type-checks a CEL expression that must produce a string or a list of strings.
+// Because the referring resource is untyped, expressions that produce dyn are allowed and checked when they are evaluated.
+func compileReferenceExpression(expression string) (cel.Program, error) {
+	ast, issues := referenceCELEnv.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	outputType := ast.OutputType()
+	switch {
+	case outputType.IsExactType(cel.StringType):
+	case outputType.IsExactType(cel.ListType(cel.StringType)):
+	case outputType.IsExactType(cel.DynType):
+	case outputType.IsExactType(cel.ListType(cel.DynType)):
+	default:
+		return nil, fmt.Errorf("must evaluate to a string or list of strings, not %v", outputType)
+	}
+
+	program, err := referenceCELEnv.Program(ast, cel.CostLimit(referenceCELCostLimit))
+	if err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+// cachedReferenceExpression is compileReferenceExpression, but only compiles each expression once.
+func cachedReferenceExpression(expression string) (cel.Program, error) {
+	referenceCELPrograms.Lock()
+	defer referenceCELPrograms.Unlock()
+
+	if program, ok := referenceCELPrograms.programs[expression]; ok {
+		return program, nil
+	}
+	program, err := compileReferenceExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	referenceCELPrograms.programs[expression] = program
+	return program, nil
+}
+
+// evaluateCELExpression runs the expression against the referringResource and returns every result as a string.
+func evaluateCELExpression(ctx context.Context, fieldPath *field.Path, expression string, referringResource *Resource) ([]string, error) {
+	program, err := cachedReferenceExpression(expression)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling [%v]: %q: %w", fieldPath, expression, err)
+	}
+
+	result, _, err := program.ContextEval(ctx, map[string]interface{}{
+		referenceCELVariableName: referringResource.Content.UnstructuredContent(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error finding value for %v from %v with expression: %w", fieldPath, referringResource.ID(), err)
+	}
+
+	switch result.Type() {
+	case types.StringType:
+		return []string{result.Value().(string)}, nil
+	case types.ListType:
+		resultStrings, err := result.ConvertToNative(reflect.TypeOf([]string{}))
+		if err != nil {
+			return nil, fmt.Errorf("[%v] expression must produce a list of strings: %w", fieldPath, err)
+		}
+		return resultStrings.([]string), nil
+	default:
+		return nil, fmt.Errorf("[%v] unexpected result type %v for %v", fieldPath, result.Type(), result)
+	}
+}
@@ -0,0 +1,69 @@
+package libraryinputresources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestEvaluateCELExpression(t *testing.T) {
+	items := []interface{}{}
+	for i := 0; i < 200; i++ {
+		items = append(items, fmt.Sprintf("item-%d", i))
+	}
+	referringResource := &Resource{
+		Content: &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "referring", "namespace": "openshift-config"},
+			"spec":       map[string]interface{}{"items": items},
+		}},
+	}
+
+	tests := []struct {
+		name          string
+		expression    string
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:       "string",
+			expression: `object.metadata.name + "-ca"`,
+			expected:   []string{"referring-ca"},
+		},
+		{
+			name:       "list",
+			expression: `object.spec.items.filter(i, i.endsWith("-1"))`,
+			expected:   []string{"item-1"},
+		},
+		{
+			name:          "over the cost limit",
+			expression:    `object.spec.items.map(a, object.spec.items.map(b, object.spec.items.map(c, a + b + c)))[0][0]`,
+			expectedError: "cost limit exceeded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// evaluate twice, the second evaluation uses the cached program.
+			for i := 0; i < 2; i++ {
+				actual, err := evaluateCELExpression(context.Background(), field.NewPath("nameExpression"), tt.expression, referringResource)
+				if len(tt.expectedError) > 0 {
+					if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+						t.Fatalf("expected error containing %q, got %v", tt.expectedError, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if strings.Join(actual, ",") != strings.Join(tt.expected, ",") {
+					t.Errorf("expected %v, got %v", tt.expected, actual)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package libraryinputresources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+)
+
+type InputResourceDifferenceType string
+
+const (
+	InputResourceOnlyInLHS InputResourceDifferenceType = "OnlyInLHS"
+	InputResourceOnlyInRHS InputResourceDifferenceType = "OnlyInRHS"
+	InputResourceChanged   InputResourceDifferenceType = "Changed"
+)
+
+// InputResourceDifference is a single resource that an operator consumes that differs between two clusters.
+type InputResourceDifference struct {
+	// FieldPath is the resourceList in the InputResources that selected the resource.
+	FieldPath string `json:"fieldPath"`
+	// Resource identifies the resource as group/resource/namespace/name.
+	Resource string                      `json:"resource"`
+	Type     InputResourceDifferenceType `json:"type"`
+	// Diff is set for Changed resources.  It ignores metadata that is different for every cluster, like uid.
+	Diff string `json:"diff,omitempty"`
+}
+
+func (d InputResourceDifference) String() string {
+	switch d.Type {
+	case InputResourceOnlyInLHS:
+		return fmt.Sprintf("%v: %v only in lhs", d.FieldPath, d.Resource)
+	case InputResourceOnlyInRHS:
+		return fmt.Sprintf("%v: %v only in rhs", d.FieldPath, d.Resource)
+	default:
+		return fmt.Sprintf("%v: %v changed (-lhs +rhs):\n%v", d.FieldPath, d.Resource, d.Diff)
+	}
+}
+
+// volatileMetadataFields differ for every cluster and every write, so they are not interesting when comparing clusters.
+var volatileMetadataFields = [][]string{
+	{"metadata", "uid"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "creationTimestamp"},
+	{"metadata", "managedFields"},
+}
+
+// DiffRequiredInputResources reads the inputResources from both clients and returns every resource that differs.
+// Only the resources an operator consumes are compared, so unrelated differences between the clusters are ignored.
+func DiffRequiredInputResources(ctx context.Context, inputResources *InputResources, lhsDynamicClient, rhsDynamicClient dynamic.Interface) ([]InputResourceDifference, error) {
+	ret := []InputResourceDifference{}
+	errs := []error{}
+	for _, currList := range resourceListsForInputResources(inputResources) {
+		lhsResources, err := GetRequiredInputResourcesForResourceList(ctx, currList.resourceList, lhsDynamicClient)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed reading lhs %v: %w", currList.fieldPath, err))
+			continue
+		}
+		rhsResources, err := GetRequiredInputResourcesForResourceList(ctx, currList.resourceList, rhsDynamicClient)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed reading rhs %v: %w", currList.fieldPath, err))
+			continue
+		}
+		ret = append(ret, diffResources(currList.fieldPath.String(), lhsResources, rhsResources)...)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return ret, nil
+}
+
+func diffResources(fieldPath string, lhsResources, rhsResources []*Resource) []InputResourceDifference {
+	lhsByID := map[string]*Resource{}
+	for _, curr := range lhsResources {
+		lhsByID[curr.ID()] = curr
+	}
+	rhsByID := map[string]*Resource{}
+	for _, curr := range rhsResources {
+		rhsByID[curr.ID()] = curr
+	}
+
+	ret := []InputResourceDifference{}
+	for _, id := range sets.List(sets.KeySet(lhsByID).Union(sets.KeySet(rhsByID))) {
+		lhs, inLHS := lhsByID[id]
+		rhs, inRHS := rhsByID[id]
+		switch {
+		case !inRHS:
+			ret = append(ret, InputResourceDifference{FieldPath: fieldPath, Resource: id, Type: InputResourceOnlyInLHS})
+		case !inLHS:
+			ret = append(ret, InputResourceDifference{FieldPath: fieldPath, Resource: id, Type: InputResourceOnlyInRHS})
+		default:
+			lhsContent := withoutVolatileMetadata(lhs.Content)
+			rhsContent := withoutVolatileMetadata(rhs.Content)
+			if reflect.DeepEqual(lhsContent, rhsContent) {
+				continue
+			}
+			ret = append(ret, InputResourceDifference{
+				FieldPath: fieldPath,
+				Resource:  id,
+				Type:      InputResourceChanged,
+				Diff:      strings.TrimSpace(cmp.Diff(lhsContent, rhsContent)),
+			})
+		}
+	}
+	return ret
+}
+
+func withoutVolatileMetadata(in *unstructured.Unstructured) map[string]interface{} {
+	ret := in.DeepCopy().Object
+	for _, fields := range volatileMetadataFields {
+		unstructured.RemoveNestedField(ret, fields...)
+	}
+	return ret
+}
@@ -0,0 +1,137 @@
+package libraryinputresources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigMaps(t *testing.T, mustGatherDir, content string) {
+	t.Helper()
+
+	filename := filepath.Join(mustGatherDir, "namespaces", "openshift-config", "core", "configmaps.yaml")
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiffRequiredInputResources(t *testing.T) {
+	lhsDir := t.TempDir()
+	writeConfigMaps(t, lhsDir, `apiVersion: v1
+kind: ConfigMapList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: same
+    namespace: openshift-config
+    uid: lhs-uid
+    resourceVersion: "1"
+  data:
+    key: value
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: changed
+    namespace: openshift-config
+  data:
+    key: lhs-value
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: removed
+    namespace: openshift-config
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: not-an-input
+    namespace: openshift-config
+  data:
+    key: lhs-value
+`)
+	rhsDir := t.TempDir()
+	writeConfigMaps(t, rhsDir, `apiVersion: v1
+kind: ConfigMapList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: same
+    namespace: openshift-config
+    uid: rhs-uid
+    resourceVersion: "2"
+  data:
+    key: value
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: changed
+    namespace: openshift-config
+  data:
+    key: rhs-value
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: added
+    namespace: openshift-config
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: not-an-input
+    namespace: openshift-config
+  data:
+    key: rhs-value
+`)
+
+	inputResources := &InputResources{
+		ApplyConfigurationResources: ResourceList{
+			ExactResources: []ExactResourceID{
+				ExactConfigMap("openshift-config", "same"),
+				ExactConfigMap("openshift-config", "changed"),
+				ExactConfigMap("openshift-config", "removed"),
+				ExactConfigMap("openshift-config", "added"),
+			},
+		},
+	}
+
+	lhsClient, err := NewDynamicClientFromMustGather(lhsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rhsClient, err := NewDynamicClientFromMustGather(rhsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := DiffRequiredInputResources(context.Background(), inputResources, lhsClient, rhsClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(actual) != 3 {
+		t.Fatalf("expected 3 differences, got %v", actual)
+	}
+	expected := []struct {
+		resource       string
+		differenceType InputResourceDifferenceType
+	}{
+		{resource: "/configmaps/openshift-config/added", differenceType: InputResourceOnlyInRHS},
+		{resource: "/configmaps/openshift-config/changed", differenceType: InputResourceChanged},
+		{resource: "/configmaps/openshift-config/removed", differenceType: InputResourceOnlyInLHS},
+	}
+	for i := range expected {
+		if actual[i].FieldPath != "applyConfigurationResources" {
+			t.Errorf("%d: unexpected fieldPath %q", i, actual[i].FieldPath)
+		}
+		if actual[i].Resource != expected[i].resource || actual[i].Type != expected[i].differenceType {
+			t.Errorf("%d: expected %v %v, got %v %v", i, expected[i].differenceType, expected[i].resource, actual[i].Type, actual[i].Resource)
+		}
+	}
+	if diff := actual[1].Diff; !strings.Contains(diff, `"lhs-value"`) || !strings.Contains(diff, `"rhs-value"`) {
+		t.Errorf("expected diff of data, got %v", diff)
+	}
+}
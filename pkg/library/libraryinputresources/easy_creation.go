@@ -12,6 +12,18 @@ func ExactResource(group, version, resource, namespace, name string) ExactResour
 	}
 }
 
+func GeneratedResource(group, version, resource, namespace, name string) GeneratedResourceID {
+	return GeneratedResourceID{
+		InputResourceTypeIdentifier: InputResourceTypeIdentifier{
+			Group:    group,
+			Version:  version,
+			Resource: resource,
+		},
+		Namespace:     namespace,
+		GeneratedName: name,
+	}
+}
+
 func ExactSecret(namespace, name string) ExactResourceID {
 	return ExactResource("", "v1", "secrets", namespace, name)
 }
@@ -64,6 +76,10 @@ func ExactConfigResource(resource string) ExactResourceID {
 	return ExactResource("config.openshift.io", "v1", resource, "", "cluster")
 }
 
+func GeneratedCSR(generateName string) GeneratedResourceID {
+	return GeneratedResource("certificates.k8s.io", "v1", "certificatesigningrequests", "", generateName)
+}
+
 func SecretIdentifierType() InputResourceTypeIdentifier {
 	return InputResourceTypeIdentifier{
 		Group:    "",
@@ -38,8 +38,19 @@ func (o *inputResourcesOptions) Run(ctx context.Context) error {
 	convertedResources := convertOutputToInput(outputResources)
 	inputResources.ApplyConfigurationResources.ExactResources = append(inputResources.ApplyConfigurationResources.ExactResources, convertedResources.ApplyConfigurationResources.ExactResources...)
 	inputResources.ApplyConfigurationResources.GeneratedNameResources = append(inputResources.ApplyConfigurationResources.GeneratedNameResources, convertedResources.ApplyConfigurationResources.GeneratedNameResources...)
+	inputResources.ApplyConfigurationResources.LabelSelectedResources = append(inputResources.ApplyConfigurationResources.LabelSelectedResources, convertedResources.ApplyConfigurationResources.LabelSelectedResources...)
 
 	errs = append(errs, validateInputResources(inputResources)...)
+	discoveryClient, err := NewDefaultDiscoveryClient()
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		warnings, discoveryErrs := ValidateInputResourcesAgainstDiscovery(inputResources, discoveryClient)
+		for _, warning := range warnings {
+			fmt.Fprintf(o.streams.ErrOut, "warning: %v\n", warning)
+		}
+		errs = append(errs, discoveryErrs...)
+	}
 
 	inputResourcesYAML, err := yaml.Marshal(inputResources)
 	if err != nil {
@@ -80,6 +91,21 @@ func convertOutputToInput(outputResources *libraryoutputresources.OutputResource
 				GeneratedName: curr.GeneratedName,
 			})
 		}
+		for _, curr := range currResourceList.LabelSelectedResources {
+			if len(curr.LabelSelector.MatchLabels) == 0 && len(curr.LabelSelector.MatchExpressions) == 0 {
+				// an empty selector never matches an output, so it must not select every input either.
+				continue
+			}
+			inputResources.ApplyConfigurationResources.LabelSelectedResources = append(inputResources.ApplyConfigurationResources.LabelSelectedResources, LabelSelectedResource{
+				InputResourceTypeIdentifier: InputResourceTypeIdentifier{
+					Group:    curr.Group,
+					Version:  curr.Version,
+					Resource: curr.Resource,
+				},
+				Namespace:     curr.Namespace,
+				LabelSelector: *curr.LabelSelector.DeepCopy(),
+			})
+		}
 	}
 
 	return inputResources
@@ -3,6 +3,8 @@ package libraryinputresources
 import (
 	"reflect"
 	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_validateInputResources(t *testing.T) {
@@ -44,6 +46,72 @@ func Test_validateInputResources(t *testing.T) {
 			},
 			want: []string{},
 		},
+		{
+			name: "missing generated name",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						GeneratedNameResources: []GeneratedResourceID{
+							GeneratedCSR("system:openshift:openshift-authenticator-"),
+							GeneratedResource("certificates.k8s.io", "v1", "certificatesigningrequests", "", ""),
+						},
+					},
+				},
+			},
+			want: []string{
+				"applyConfigurationResources.generatedNameResources[1].name: Required value: must be present",
+			},
+		},
+		{
+			name: "good matchExpressions",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						LabelSelectedResources: []LabelSelectedResource{
+							{
+								InputResourceTypeIdentifier: InputResourceTypeIdentifier{Version: "v1", Resource: "configmaps"},
+								Namespace:                   "openshift-kube-apiserver",
+								LabelSelector: metav1.LabelSelector{
+									MatchLabels: map[string]string{"app": "installer"},
+									MatchExpressions: []metav1.LabelSelectorRequirement{
+										{Key: "revision", Operator: metav1.LabelSelectorOpExists},
+										{Key: "component", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "bad matchExpressions",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						LabelSelectedResources: []LabelSelectedResource{
+							{
+								InputResourceTypeIdentifier: InputResourceTypeIdentifier{Version: "v1", Resource: "configmaps"},
+								Namespace:                   "openshift-kube-apiserver",
+								LabelSelector: metav1.LabelSelector{
+									MatchExpressions: []metav1.LabelSelectorRequirement{
+										{Key: "revision", Operator: metav1.LabelSelectorOpExists, Values: []string{"1"}},
+										{Key: "component", Operator: metav1.LabelSelectorOpIn},
+										{Key: "component", Operator: "Like", Values: []string{"a"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				`applyConfigurationResources.labelSelectedResources[0].labelSelector.matchExpressions[0].values: Forbidden: may not be specified when ` + "`operator`" + ` is 'Exists' or 'DoesNotExist'`,
+				`applyConfigurationResources.labelSelectedResources[0].labelSelector.matchExpressions[1].values: Required value: must be specified when ` + "`operator`" + ` is 'In' or 'NotIn'`,
+				`applyConfigurationResources.labelSelectedResources[0].labelSelector.matchExpressions[2].operator: Invalid value: "Like": not a valid selector operator`,
+			},
+		},
 		{
 			name: "bad jsonpath",
 			args: args{
@@ -95,6 +163,377 @@ func Test_validateInputResources(t *testing.T) {
 			},
 			want: []string{},
 		},
+		{
+			name: "good explicit reference",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						ResourceReferences: []ResourceReference{
+							{
+								ReferringResource: ExactConfigResource("ingresses"),
+								Type:              ExplicitNamespacedReferenceType,
+								ExplicitNamespacedReference: &ExplicitNamespacedReference{
+									InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "route.openshift.io", Version: "v1", Resource: "routes"},
+									NamespaceJSONPath:           `$.spec.componentRoutes[*].namespace`,
+									NameJSONPath:                `$.spec.componentRoutes[*].name`,
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "bad explicit reference",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						ResourceReferences: []ResourceReference{
+							{
+								ReferringResource: ExactConfigResource("ingresses"),
+								Type:              ExplicitNamespacedReferenceType,
+								ExplicitNamespacedReference: &ExplicitNamespacedReference{
+									InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "route.openshift.io", Resource: "routes"},
+									NamespaceJSONPath:           "please DON'T compile[AND foo]",
+									NameJSONPath:                `$.spec.componentRoutes[*].name`,
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				`applyConfigurationResources.resourceReferences[0].explicitNamespacedReference.version: Required value: must be present`,
+				`applyConfigurationResources.resourceReferences[0].explicitNamespacedReference.namespaceJSONPath: Invalid value: "please DON'T compile[AND foo]": parsing error: please DON'T compile[AND foo]	:1:8 - 1:11 unexpected Ident while scanning operator`,
+			},
+		},
+		{
+			name: "missing explicit reference",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						ResourceReferences: []ResourceReference{
+							{
+								ReferringResource: ExactConfigResource("ingresses"),
+								Type:              ExplicitNamespacedReferenceType,
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				`applyConfigurationResources.resourceReferences[0].explicitNamespacedReference: Required value: must be present for type=ExplicitNamespacedReference`,
+			},
+		},
+		{
+			name: "good cluster scoped reference",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						ResourceReferences: []ResourceReference{
+							{
+								ReferringResource: ExactClusterOperator("authentication"),
+								Type:              ClusterScopedReferenceType,
+								ClusterScopedReference: &ClusterScopedReference{
+									InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1", Resource: "oauths"},
+									NameJSONPath:                `$.status.relatedObjects[?(@.resource == "oauths")].name`,
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "bad cluster scoped reference",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						ResourceReferences: []ResourceReference{
+							{
+								ReferringResource: ExactClusterOperator("authentication"),
+								Type:              ClusterScopedReferenceType,
+								ClusterScopedReference: &ClusterScopedReference{
+									InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1"},
+									NameJSONPath:                "",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				`applyConfigurationResources.resourceReferences[0].clusterScopedReference.resource: Required value: must be present`,
+				`applyConfigurationResources.resourceReferences[0].clusterScopedReference.nameJSONPath: Invalid value: "": parsing error: 	 - 1:1 unexpected EOF while scanning extensions`,
+			},
+		},
+		{
+			name: "missing cluster scoped reference",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						ResourceReferences: []ResourceReference{
+							{
+								ReferringResource: ExactClusterOperator("authentication"),
+								Type:              ClusterScopedReferenceType,
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				`applyConfigurationResources.resourceReferences[0].clusterScopedReference: Required value: must be present for type=ClusterScopedReference`,
+			},
+		},
+		{
+			name: "unknown reference type",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						ResourceReferences: []ResourceReference{
+							{
+								ReferringResource: ExactClusterOperator("authentication"),
+								Type:              "Unknown",
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				`applyConfigurationResources.resourceReferences[0].type: Unsupported value: "Unknown": supported values: "ImplicitNamespacedReference", "ExplicitNamespacedReference", "ClusterScopedReference"`,
+			},
+		},
+		{
+			name: "good cel expressions",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						ResourceReferences: []ResourceReference{
+							{
+								ReferringResource: ExactConfigResource("ingresses"),
+								Type:              ExplicitNamespacedReferenceType,
+								ExplicitNamespacedReference: &ExplicitNamespacedReference{
+									InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "route.openshift.io", Version: "v1", Resource: "routes"},
+									NamespaceExpression:         `object.spec.componentRoutes.map(r, r.namespace)`,
+									NameExpression:              `object.spec.componentRoutes.map(r, r.name)`,
+								},
+							},
+							{
+								ReferringResource: ExactConfigResource("apiservers"),
+								Type:              ImplicitNamespacedReferenceType,
+								ImplicitNamespacedReference: &ImplicitNamespacedReference{
+									InputResourceTypeIdentifier: SecretIdentifierType(),
+									Namespace:                   "openshift-config",
+									NameExpression:              `has(object.spec.servingCerts) ? object.spec.servingCerts.namedCertificates.map(c, c.servingCertificate.name) : []`,
+								},
+							},
+							{
+								ReferringResource: ExactClusterOperator("authentication"),
+								Type:              ClusterScopedReferenceType,
+								ClusterScopedReference: &ClusterScopedReference{
+									InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1", Resource: "oauths"},
+									NameExpression:              `"cluster"`,
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "bad cel expressions",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						ResourceReferences: []ResourceReference{
+							{
+								ReferringResource: ExactConfigResource("ingresses"),
+								Type:              ExplicitNamespacedReferenceType,
+								ExplicitNamespacedReference: &ExplicitNamespacedReference{
+									InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "route.openshift.io", Version: "v1", Resource: "routes"},
+									NamespaceJSONPath:           `$.spec.componentRoutes[*].namespace`,
+									NamespaceExpression:         `object.spec.componentRoutes.map(r, r.namespace)`,
+									NameExpression:              `object.spec.componentRoutes.size()`,
+								},
+							},
+							{
+								ReferringResource: ExactClusterOperator("authentication"),
+								Type:              ClusterScopedReferenceType,
+								ClusterScopedReference: &ClusterScopedReference{
+									InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1", Resource: "oauths"},
+									NameExpression:              `oauth.metadata.name`,
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				`applyConfigurationResources.resourceReferences[0].explicitNamespacedReference.namespaceJSONPath: Forbidden: may not be set when applyConfigurationResources.resourceReferences[0].explicitNamespacedReference.namespaceExpression is set`,
+				`applyConfigurationResources.resourceReferences[0].explicitNamespacedReference.nameExpression: Invalid value: "object.spec.componentRoutes.size()": must evaluate to a string or list of strings, not int`,
+				"applyConfigurationResources.resourceReferences[1].clusterScopedReference.nameExpression: Invalid value: \"oauth.metadata.name\": ERROR: <input>:1:1: undeclared reference to 'oauth' (in container '')\n | oauth.metadata.name\n | ^",
+			},
+		},
+		{
+			name: "good chained references",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						LabelSelectedResources: []LabelSelectedResource{
+							{
+								Name:                        "operator-secrets",
+								InputResourceTypeIdentifier: SecretIdentifierType(),
+								Namespace:                   "openshift-config",
+								LabelSelector:               metav1.LabelSelector{MatchLabels: map[string]string{"app": "operator"}},
+							},
+						},
+						ResourceReferences: []ResourceReference{
+							{
+								Name:              "serving-certs",
+								ReferringResource: ExactConfigResource("apiservers"),
+								Type:              ImplicitNamespacedReferenceType,
+								ImplicitNamespacedReference: &ImplicitNamespacedReference{
+									InputResourceTypeIdentifier: SecretIdentifierType(),
+									Namespace:                   "openshift-config",
+									NameJSONPath:                `$.spec.servingCerts.namedCertificates[*].servingCertificate.name`,
+								},
+							},
+							{
+								Name: "ca-bundles",
+								ReferringResourcesFrom: &ReferringResourcesFrom{
+									ResourceReferences:     []string{"serving-certs", "ca-bundles"},
+									LabelSelectedResources: []string{"operator-secrets"},
+								},
+								Type: ImplicitNamespacedReferenceType,
+								ImplicitNamespacedReference: &ImplicitNamespacedReference{
+									InputResourceTypeIdentifier: InputResourceTypeIdentifier{Version: "v1", Resource: "configmaps"},
+									Namespace:                   "openshift-config",
+									NameJSONPath:                `$.metadata.name`,
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "bad chained references",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						LabelSelectedResources: []LabelSelectedResource{
+							{
+								Name:                        "operator-secrets",
+								InputResourceTypeIdentifier: SecretIdentifierType(),
+							},
+							{
+								Name:                        "operator-secrets",
+								InputResourceTypeIdentifier: SecretIdentifierType(),
+							},
+						},
+						ResourceReferences: []ResourceReference{
+							{
+								Name:              "serving-certs",
+								ReferringResource: ExactConfigResource("apiservers"),
+								ReferringResourcesFrom: &ReferringResourcesFrom{
+									ResourceReferences: []string{"missing"},
+								},
+								Type: ImplicitNamespacedReferenceType,
+								ImplicitNamespacedReference: &ImplicitNamespacedReference{
+									InputResourceTypeIdentifier: SecretIdentifierType(),
+									Namespace:                   "openshift-config",
+									NameJSONPath:                `$.metadata.name`,
+								},
+							},
+							{
+								Name:                   "serving-certs",
+								ReferringResourcesFrom: &ReferringResourcesFrom{},
+								Type:                   ImplicitNamespacedReferenceType,
+								ImplicitNamespacedReference: &ImplicitNamespacedReference{
+									InputResourceTypeIdentifier: SecretIdentifierType(),
+									Namespace:                   "openshift-config",
+									NameJSONPath:                `$.metadata.name`,
+								},
+							},
+							{
+								Type: ImplicitNamespacedReferenceType,
+								ImplicitNamespacedReference: &ImplicitNamespacedReference{
+									InputResourceTypeIdentifier: SecretIdentifierType(),
+									Namespace:                   "openshift-config",
+									NameJSONPath:                `$.metadata.name`,
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				`applyConfigurationResources.labelSelectedResources[1].name: Duplicate value: "operator-secrets"`,
+				`applyConfigurationResources.resourceReferences[1].name: Duplicate value: "serving-certs"`,
+				`applyConfigurationResources.resourceReferences[0].referringResource: Forbidden: may not be set when referringResourcesFrom is set`,
+				`applyConfigurationResources.resourceReferences[0].referringResourcesFrom.resourceReferences[0]: Not found: "missing"`,
+				`applyConfigurationResources.resourceReferences[1].referringResourcesFrom: Required value: at least one of resourceReferences and labelSelectedResources must be set`,
+				`applyConfigurationResources.resourceReferences[2].referringResource: Required value: one of referringResource and referringResourcesFrom must be present`,
+			},
+		},
+		{
+			name: "bad fields",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						ExactResources: []ExactResourceID{
+							{
+								InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1", Resource: "clusterversions"},
+								Name:                        "version",
+								Fields:                      []string{".status.history[*].version", ".status.history[0]"},
+							},
+						},
+						LabelSelectedResources: []LabelSelectedResource{
+							{
+								InputResourceTypeIdentifier: InputResourceTypeIdentifier{Version: "v1", Resource: "configmaps"},
+								Fields:                      []string{"$.data['config.yaml']", ".data..foo"},
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				`applyConfigurationResources.exactResources[0].fields[1]: Invalid value: ".status.history[0]": only .name, ['name'], and [*] are supported, found "[0]" in ".status.history[0]"`,
+				`applyConfigurationResources.labelSelectedResources[0].fields[1]: Invalid value: ".data..foo": empty field name in ".data..foo"`,
+			},
+		},
+		{
+			name: "bad ownedResources",
+			args: args{
+				obj: &InputResources{
+					ApplyConfigurationResources: ResourceList{
+						OwnedResources: []OwnedResource{
+							{
+								Owner: ExactDeployment("openshift-authentication", "oauth-openshift"),
+								OwnedResourceTypes: []InputResourceTypeIdentifier{
+									{Group: "apps", Version: "v1", Resource: "replicasets"},
+									{Group: "", Resource: "pods"},
+								},
+								Namespace: "openshift-config",
+							},
+							{
+								Owner: ExactClusterOperator("authentication"),
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				`applyConfigurationResources.ownedResources[0].ownedResourceTypes[1].version: Required value: must be present`,
+				`applyConfigurationResources.ownedResources[0].namespace: Invalid value: "openshift-config": must match the namespace of the owner, resources cannot be owned across namespaces`,
+				`applyConfigurationResources.ownedResources[1].ownedResourceTypes: Required value: must have at least one entry`,
+				`applyConfigurationResources.ownedResources[1].namespace: Required value: must be present for cluster-scoped owners`,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -109,3 +548,139 @@ func Test_validateInputResources(t *testing.T) {
 		})
 	}
 }
+
+func Test_ValidateInputResourcesAgainstDiscovery(t *testing.T) {
+	discoveryClient, err := NewDefaultDiscoveryClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		obj          *InputResources
+		want         []string
+		wantWarnings []string
+	}{
+		{
+			name: "served resources",
+			obj: &InputResources{
+				ApplyConfigurationResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						ExactResource("config.openshift.io", "v1", "apiservers", "", "cluster"),
+						ExactResource("", "v1", "secrets", "openshift-config", "serving-cert"),
+					},
+					LabelSelectedResources: []LabelSelectedResource{
+						{
+							InputResourceTypeIdentifier: InputResourceTypeIdentifier{Version: "v1", Resource: "configmaps"},
+							LabelSelector:               metav1.LabelSelector{MatchLabels: map[string]string{"app": "installer"}},
+						},
+					},
+					ResourceReferences: []ResourceReference{
+						{
+							ReferringResource: ExactResource("config.openshift.io", "v1", "apiservers", "", "cluster"),
+							Type:              ImplicitNamespacedReferenceType,
+							ImplicitNamespacedReference: &ImplicitNamespacedReference{
+								InputResourceTypeIdentifier: InputResourceTypeIdentifier{Version: "v1", Resource: "secrets"},
+								Namespace:                   "openshift-config",
+								NameJSONPath:                `.spec.servingCerts.namedCertificates[*].servingCertificate.name`,
+							},
+						},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "resource typo",
+			obj: &InputResources{
+				ApplyConfigurationResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						ExactResource("", "v1", "configmap", "openshift-config", "foo"),
+					},
+				},
+			},
+			want: []string{
+				`applyConfigurationResources.exactResources[0].resource: Not found: "configmap.v1"`,
+			},
+		},
+		{
+			name: "unknown group",
+			obj: &InputResources{
+				ApplyConfigurationResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						ExactResource("example.openshift.io", "v1", "examples", "", "cluster"),
+					},
+				},
+			},
+			want: []string{},
+			wantWarnings: []string{
+				`applyConfigurationResources.exactResources[0].resource: examples.v1.example.openshift.io is not served`,
+			},
+		},
+		{
+			name: "wrong namespace scoping",
+			obj: &InputResources{
+				ApplyConfigurationResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						ExactResource("config.openshift.io", "v1", "apiservers", "openshift-config", "cluster"),
+						ExactResource("", "v1", "secrets", "", "serving-cert"),
+					},
+					ResourceReferences: []ResourceReference{
+						{
+							ReferringResource: ExactResource("config.openshift.io", "v1", "apiservers", "", "cluster"),
+							Type:              ClusterScopedReferenceType,
+							ClusterScopedReference: &ClusterScopedReference{
+								InputResourceTypeIdentifier: InputResourceTypeIdentifier{Version: "v1", Resource: "secrets"},
+								NameJSONPath:                `.spec.foo`,
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				"applyConfigurationResources.exactResources[0].namespace: Forbidden: apiservers.v1.config.openshift.io is cluster-scoped",
+				"applyConfigurationResources.exactResources[1].namespace: Required value: secrets.v1 is namespaced",
+				`applyConfigurationResources.resourceReferences[0].clusterScopedReference.resource: Invalid value: "secrets": must be a cluster-scoped resource`,
+			},
+		},
+		{
+			name: "different versions",
+			obj: &InputResources{
+				ApplyConfigurationResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						ExactResource("admissionregistration.k8s.io", "v1", "validatingadmissionpolicies", "", "foo"),
+					},
+				},
+				OperandResources: OperandResourceList{
+					ManagementResources: ResourceList{
+						ExactResources: []ExactResourceID{
+							ExactResource("admissionregistration.k8s.io", "v1beta1", "validatingadmissionpolicies", "", "bar"),
+						},
+					},
+				},
+			},
+			want: []string{},
+			wantWarnings: []string{
+				"operandResources.managementResources.exactResources[0].version: v1beta1 differs from v1 used by applyConfigurationResources.exactResources[0]",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, errs := ValidateInputResourcesAgainstDiscovery(tt.obj, discoveryClient)
+			actualStrings := []string{}
+			for _, curr := range errs {
+				actualStrings = append(actualStrings, curr.Error())
+			}
+			if !reflect.DeepEqual(actualStrings, tt.want) {
+				t.Errorf("ValidateInputResourcesAgainstDiscovery() = %v", actualStrings)
+			}
+			if len(tt.wantWarnings) == 0 {
+				tt.wantWarnings = []string{}
+			}
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("ValidateInputResourcesAgainstDiscovery() warnings = %v", warnings)
+			}
+		})
+	}
+}
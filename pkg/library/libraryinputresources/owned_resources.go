@@ -0,0 +1,91 @@
+package libraryinputresources
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+)
+
+type ownedResourceMatch struct {
+	resource *Resource
+	// owner is the resource in the ownerReferences of resource that caused it to be selected.
+	owner *Resource
+}
+
+// getOwnedResources lists the ownedResourceTypes and returns those owned by owner, or when recursive, by any owned resource.
+func getOwnedResources(ctx context.Context, dynamicClient dynamic.Interface, ownedResource OwnedResource, owner *Resource) ([]ownedResourceMatch, error) {
+	namespace := ownedResource.Namespace
+	if len(namespace) == 0 {
+		namespace = owner.Content.GetNamespace()
+	}
+
+	candidates := []*Resource{}
+	for _, ownedResourceType := range ownedResource.OwnedResourceTypes {
+		gvr := schema.GroupVersionResource{
+			Group:    ownedResourceType.Group,
+			Version:  ownedResourceType.Version,
+			Resource: ownedResourceType.Resource,
+		}
+		unstructuredList, err := dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed getting list of %v in %q: %w", resourceTypeString(gvr), namespace, err)
+		}
+		for i := range unstructuredList.Items {
+			candidates = append(candidates, &Resource{
+				ResourceType: gvr,
+				Content:      &unstructuredList.Items[i],
+			})
+		}
+	}
+
+	ret := []ownedResourceMatch{}
+	selected := sets.New[string](owner.ID())
+	owners := []*Resource{owner}
+	for len(owners) > 0 {
+		nextOwners := []*Resource{}
+		for _, candidate := range candidates {
+			if selected.Has(candidate.ID()) {
+				continue
+			}
+			for _, currOwner := range owners {
+				if !isOwnedBy(candidate, currOwner) {
+					continue
+				}
+				selected.Insert(candidate.ID())
+				ret = append(ret, ownedResourceMatch{resource: candidate, owner: currOwner})
+				nextOwners = append(nextOwners, candidate)
+				break
+			}
+		}
+		if !ownedResource.Recursive {
+			break
+		}
+		owners = nextOwners
+	}
+
+	return ret, nil
+}
+
+// isOwnedBy returns true if an ownerReference of resource refers to owner.  The uid is only compared when both are
+// present, because hand-written test fixtures often leave it out.
+func isOwnedBy(resource, owner *Resource) bool {
+	ownerGroup := owner.Content.GroupVersionKind().Group
+	for _, ownerReference := range resource.Content.GetOwnerReferences() {
+		referenceGroupVersion, err := schema.ParseGroupVersion(ownerReference.APIVersion)
+		if err != nil {
+			continue
+		}
+		if referenceGroupVersion.Group != ownerGroup || ownerReference.Kind != owner.Content.GetKind() || ownerReference.Name != owner.Content.GetName() {
+			continue
+		}
+		if len(ownerReference.UID) > 0 && len(owner.Content.GetUID()) > 0 && ownerReference.UID != owner.Content.GetUID() {
+			continue
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package libraryinputresources
+
+import (
+	"fmt"
+
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryoutputresources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// identityFields are always kept when projecting so that manifestclient can still find the resource by name and by label.
+var identityFields = []string{
+	".apiVersion",
+	".kind",
+	".metadata.name",
+	".metadata.namespace",
+	".metadata.generateName",
+	".metadata.uid",
+	".metadata.resourceVersion",
+	".metadata.labels",
+}
+
+// projectFields returns a copy of obj that contains only the identity metadata and the listed fields.
+func projectFields(obj *unstructured.Unstructured, fields []string) (*unstructured.Unstructured, error) {
+	var projected interface{} = map[string]interface{}{}
+	for _, fieldPath := range append(append([]string{}, identityFields...), fields...) {
+		segments, err := libraryoutputresources.ParseFieldPath(fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		projected, _ = projectValue(obj.Object, projected, segments)
+	}
+
+	return &unstructured.Unstructured{Object: projected.(map[string]interface{})}, nil
+}
+
+// projectValue copies the value at segments in src into dst and returns the updated dst.
+// The bool is false if src does not contain the path, in which case dst is returned unchanged.
+func projectValue(src, dst interface{}, segments []libraryoutputresources.FieldPathSegment) (interface{}, bool) {
+	if len(segments) == 0 {
+		return runtime.DeepCopyJSONValue(src), true
+	}
+
+	segment := segments[0]
+	if segment.Wildcard {
+		srcList, ok := src.([]interface{})
+		if !ok {
+			return dst, false
+		}
+		dstList, ok := dst.([]interface{})
+		if !ok || len(dstList) != len(srcList) {
+			dstList = make([]interface{}, len(srcList))
+		}
+		for i := range srcList {
+			if projectedItem, ok := projectValue(srcList[i], dstList[i], segments[1:]); ok {
+				dstList[i] = projectedItem
+				continue
+			}
+			// keep the list positions stable for items without the field.
+			if _, isMap := srcList[i].(map[string]interface{}); isMap && dstList[i] == nil {
+				dstList[i] = map[string]interface{}{}
+			}
+		}
+		return dstList, true
+	}
+
+	srcMap, ok := src.(map[string]interface{})
+	if !ok {
+		return dst, false
+	}
+	srcValue, ok := srcMap[segment.Name]
+	if !ok {
+		return dst, false
+	}
+	dstMap, ok := dst.(map[string]interface{})
+	if !ok {
+		dstMap = map[string]interface{}{}
+	}
+	projectedValue, ok := projectValue(srcValue, dstMap[segment.Name], segments[1:])
+	if !ok {
+		return dst, false
+	}
+	dstMap[segment.Name] = projectedValue
+	return dstMap, true
+}
+
+// fieldProjections tracks which fields to keep for every selected resource.  A resource selected by any rule without
+// fields is kept whole.
+type fieldProjections struct {
+	fieldsByResource map[string]sets.Set[string]
+	wholeResources   sets.Set[string]
+}
+
+func newFieldProjections() *fieldProjections {
+	return &fieldProjections{
+		fieldsByResource: map[string]sets.Set[string]{},
+		wholeResources:   sets.New[string](),
+	}
+}
+
+func (p *fieldProjections) keep(fields []string, resources ...*Resource) {
+	for _, resource := range resources {
+		if len(fields) == 0 {
+			p.wholeResources.Insert(resource.ID())
+			continue
+		}
+		if _, ok := p.fieldsByResource[resource.ID()]; !ok {
+			p.fieldsByResource[resource.ID()] = sets.New[string]()
+		}
+		p.fieldsByResource[resource.ID()].Insert(fields...)
+	}
+}
+
+func (p *fieldProjections) project(resources []*Resource) ([]*Resource, error) {
+	ret := []*Resource{}
+	for _, resource := range resources {
+		fields, ok := p.fieldsByResource[resource.ID()]
+		if !ok || p.wholeResources.Has(resource.ID()) {
+			ret = append(ret, resource)
+			continue
+		}
+		projectedContent, err := projectFields(resource.Content, sets.List(fields))
+		if err != nil {
+			return nil, fmt.Errorf("failed projecting %v: %w", resource.ID(), err)
+		}
+		ret = append(ret, &Resource{
+			Filename:     resource.Filename,
+			ResourceType: resource.ResourceType,
+			Content:      projectedContent,
+		})
+	}
+	return ret, nil
+}
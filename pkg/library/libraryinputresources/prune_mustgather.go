@@ -4,30 +4,73 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/PaesslerAG/gval"
 	"github.com/PaesslerAG/jsonpath"
 	"github.com/openshift/library-go/pkg/manifestclient"
+	"github.com/openshift/multi-operator-manager/pkg/library/librarymustgather"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 )
 
+// WriteRequiredInputResourcesFromMustGather writes the applyConfigurationResources found in the must-gather to
+// targetDir.  This is the input-dir of an apply-configuration test, which does not read operandResources.
 func WriteRequiredInputResourcesFromMustGather(ctx context.Context, inputResources *InputResources, mustGatherDir, targetDir string) error {
-	actualResources, err := GetRequiredInputResourcesFromMustGather(ctx, inputResources, mustGatherDir)
+	dynamicClient, err := NewDynamicClientFromMustGather(mustGatherDir)
+	if err != nil {
+		return err
+	}
+
+	return WriteRequiredInputResourcesFromClient(ctx, inputResources, dynamicClient, targetDir)
+}
+
+func GetRequiredInputResourcesFromMustGather(ctx context.Context, inputResources *InputResources, mustGatherDir string) ([]*Resource, error) {
+	dynamicClient, err := NewDynamicClientFromMustGather(mustGatherDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetRequiredInputResourcesFromClient(ctx, inputResources, dynamicClient)
+}
+
+// WriteRequiredInputResourcesFromClient reads the applyConfigurationResources using the dynamicClient and writes them
+// to targetDir in the same layout as a must-gather.  The dynamicClient can be backed by a live cluster or a must-gather.
+func WriteRequiredInputResourcesFromClient(ctx context.Context, inputResources *InputResources, dynamicClient dynamic.Interface, targetDir string) error {
+	actualResources, err := GetRequiredInputResourcesFromClient(ctx, inputResources, dynamicClient)
+	if err != nil {
+		return err
+	}
+
+	return WriteResources(actualResources, targetDir)
+}
+
+// WriteRequiredInputAndOperandResourcesFromClient is WriteRequiredInputResourcesFromClient, but also writes the
+// operandResources into the directory of their cluster type, like ConfigurationOperandDirectory.
+func WriteRequiredInputAndOperandResourcesFromClient(ctx context.Context, inputResources *InputResources, dynamicClient dynamic.Interface, targetDir string) error {
+	actualResources, _, err := GetRequiredInputAndOperandResourcesFromClientWithReport(ctx, inputResources, dynamicClient)
 	if err != nil {
 		return err
 	}
 
+	return WriteResources(actualResources, targetDir)
+}
+
+// WriteResources writes every resource to its filename under targetDir.
+func WriteResources(actualResources []*Resource, targetDir string) error {
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
 		return fmt.Errorf("unable to create %q: %w", targetDir, err)
 	}
@@ -42,139 +85,487 @@ func WriteRequiredInputResourcesFromMustGather(ctx context.Context, inputResourc
 	return errors.Join(errs...)
 }
 
-func GetRequiredInputResourcesFromMustGather(ctx context.Context, inputResources *InputResources, mustGatherDir string) ([]*Resource, error) {
-	dynamicClient, err := NewDynamicClientFromMustGather(mustGatherDir)
-	if err != nil {
-		return nil, err
+func GetRequiredInputResourcesFromClient(ctx context.Context, inputResources *InputResources, dynamicClient dynamic.Interface) ([]*Resource, error) {
+	ret, _, err := GetRequiredInputResourcesFromClientWithReport(ctx, inputResources, dynamicClient)
+	return ret, err
+}
+
+// GetRequiredInputResourcesFromClientWithReport is GetRequiredInputResourcesFromClient, but also reports every
+// requested resource that was not found and why every returned resource was selected.
+// Only the applyConfigurationResources are returned, use GetRequiredInputAndOperandResourcesFromClientWithReport to
+// also collect the operandResources.
+func GetRequiredInputResourcesFromClientWithReport(ctx context.Context, inputResources *InputResources, dynamicClient dynamic.Interface) ([]*Resource, *InputResourcesReport, error) {
+	return getRequiredResourcesFromClientWithReport(ctx, []resourceListLocation{applyConfigurationResourceList(inputResources)}, dynamicClient)
+}
+
+// GetRequiredInputAndOperandResourcesFromClientWithReport is GetRequiredInputResourcesFromClientWithReport, but also
+// returns the operandResources.  Their filenames are prefixed with the directory of their cluster type, like
+// ConfigurationOperandDirectory, so that they can be written next to the applyConfigurationResources.
+func GetRequiredInputAndOperandResourcesFromClientWithReport(ctx context.Context, inputResources *InputResources, dynamicClient dynamic.Interface) ([]*Resource, *InputResourcesReport, error) {
+	return getRequiredResourcesFromClientWithReport(ctx, resourceListsForInputResources(inputResources), dynamicClient)
+}
+
+func getRequiredResourcesFromClientWithReport(ctx context.Context, resourceLists []resourceListLocation, dynamicClient dynamic.Interface) ([]*Resource, *InputResourcesReport, error) {
+	report := &resolutionReport{}
+	ret := []*Resource{}
+	errs := []error{}
+	for _, currList := range resourceLists {
+		pertinentUnstructureds, err := getRequiredInputResourcesForResourceList(ctx, currList.fieldPath, currList.resourceList, dynamicClient, report)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		currResources, err := unstructuredToMustGatherFormat(pertinentUnstructureds)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed formatting %v: %w", currList.fieldPath, err))
+			continue
+		}
+		for _, currResource := range currResources {
+			currResource.Filename = path.Join(currList.directory, currResource.Filename)
+		}
+		ret = append(ret, currResources...)
+	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
 	}
 
-	pertinentUnstructureds, err := GetRequiredInputResourcesForResourceList(ctx, inputResources.ApplyConfigurationResources, dynamicClient)
-	if err != nil {
-		return nil, err
+	return ret, &InputResourcesReport{
+		Missing:   report.missingInputResources(),
+		Explained: report.explainedInputResources(),
+	}, nil
+}
+
+// These are the directories that operandResources are written to, one for each type of cluster.
+// They match the libraryapplyconfiguration.ClusterType values used for output.
+const (
+	ConfigurationOperandDirectory = "Configuration"
+	ManagementOperandDirectory    = "Management"
+	UserWorkloadOperandDirectory  = "UserWorkload"
+)
+
+type resourceListLocation struct {
+	fieldPath    *field.Path
+	directory    string
+	resourceList ResourceList
+}
+
+// applyConfigurationResourceList is the location of the ApplyConfigurationResources.  They are written to the root so
+// the output can be used directly as an input-dir.
+func applyConfigurationResourceList(inputResources *InputResources) resourceListLocation {
+	return resourceListLocation{
+		fieldPath:    field.NewPath("applyConfigurationResources"),
+		resourceList: inputResources.ApplyConfigurationResources,
 	}
+}
 
-	return unstructuredToMustGatherFormat(pertinentUnstructureds)
+// resourceListsForInputResources lists every ResourceList in the inputResources with the directory its resources are
+// written to.
+func resourceListsForInputResources(inputResources *InputResources) []resourceListLocation {
+	operandPath := field.NewPath("operandResources")
+	return []resourceListLocation{
+		applyConfigurationResourceList(inputResources),
+		{
+			fieldPath:    operandPath.Child("configurationResources"),
+			directory:    ConfigurationOperandDirectory,
+			resourceList: inputResources.OperandResources.ConfigurationResources,
+		},
+		{
+			fieldPath:    operandPath.Child("managementResources"),
+			directory:    ManagementOperandDirectory,
+			resourceList: inputResources.OperandResources.ManagementResources,
+		},
+		{
+			fieldPath:    operandPath.Child("userWorkloadResources"),
+			directory:    UserWorkloadOperandDirectory,
+			resourceList: inputResources.OperandResources.UserWorkloadResources,
+		},
+	}
 }
 
+// NewDynamicClientFromMustGather reads from the must-gather at mustGatherDir, which may also be an archive.
+// See librarymustgather.NewFS.  Archives are read into memory, so use NewDynamicClientFromFS to share one read.
 func NewDynamicClientFromMustGather(mustGatherDir string) (dynamic.Interface, error) {
-	httpClient := newHTTPClientFromMustGather(mustGatherDir)
-	dynamicClient, err := dynamic.NewForConfigAndClient(&rest.Config{}, httpClient)
+	mustGatherFS, err := librarymustgather.NewFS(mustGatherDir)
 	if err != nil {
-		return nil, fmt.Errorf("failure creating dynamicClient for NewDynamicClientFromMustGather: %w", err)
+		return nil, err
+	}
+	return NewDynamicClientFromFS(mustGatherFS)
+}
+
+// NewDynamicClientFromFS reads from the must-gather content in mustGatherFS.
+func NewDynamicClientFromFS(mustGatherFS fs.FS) (dynamic.Interface, error) {
+	dynamicClient, err := dynamic.NewForConfigAndClient(&rest.Config{}, newHTTPClientFromFS(mustGatherFS))
+	if err != nil {
+		return nil, fmt.Errorf("failure creating dynamicClient for NewDynamicClientFromFS: %w", err)
 	}
 	return dynamicClient, nil
 }
 
 func NewDiscoveryClientFromMustGather(mustGatherDir string) (discovery.AggregatedDiscoveryInterface, error) {
-	httpClient := newHTTPClientFromMustGather(mustGatherDir)
-	discoveryClient, err := discovery.NewDiscoveryClientForConfigAndClient(manifestclient.RecommendedRESTConfig(), httpClient)
+	mustGatherFS, err := librarymustgather.NewFS(mustGatherDir)
+	if err != nil {
+		return nil, err
+	}
+	return NewDiscoveryClientFromFS(mustGatherFS)
+}
+
+// NewDiscoveryClientFromFS reads discovery from the must-gather content in mustGatherFS.
+func NewDiscoveryClientFromFS(mustGatherFS fs.FS) (discovery.AggregatedDiscoveryInterface, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfigAndClient(manifestclient.RecommendedRESTConfig(), newHTTPClientFromFS(mustGatherFS))
 	if err != nil {
-		return nil, fmt.Errorf("failure creating discoveryClient for NewDiscoveryClientFromMustGather: %w", err)
+		return nil, fmt.Errorf("failure creating discoveryClient for NewDiscoveryClientFromFS: %w", err)
 	}
 	return discoveryClient, nil
 }
 
-func newHTTPClientFromMustGather(mustGatherDir string) *http.Client {
-	roundTripper := manifestclient.NewRoundTripper(mustGatherDir)
+func newHTTPClientFromFS(mustGatherFS fs.FS) *http.Client {
 	return &http.Client{
-		Transport: roundTripper,
+		Transport: manifestclient.NewTestingRoundTripper(mustGatherFS),
 	}
 }
 
 var builder = gval.Full(jsonpath.Language())
 
+// maxReferenceChainDepth limits how many passes are made over chained resourceReferences.
+// Every pass follows references at least one hop further, so this bounds the length of a followed chain.
+const maxReferenceChainDepth = 10
+
 func GetRequiredInputResourcesForResourceList(ctx context.Context, resourceList ResourceList, dynamicClient dynamic.Interface) ([]*Resource, error) {
+	return getRequiredInputResourcesForResourceList(ctx, field.NewPath("resourceList"), resourceList, dynamicClient, nil)
+}
+
+// getRequiredInputResourcesForResourceList resolves the resourceList.  reportPath is the location of resourceList
+// in its InputResources and is used for entries in the report.
+func getRequiredInputResourcesForResourceList(ctx context.Context, reportPath *field.Path, resourceList ResourceList, dynamicClient dynamic.Interface, report *resolutionReport) ([]*Resource, error) {
 	instances := NewUniqueResourceSet()
+	projections := newFieldProjections()
+	keep := func(fields []string, resources ...*Resource) {
+		instances.Insert(resources...)
+		projections.keep(fields, resources...)
+	}
 	errs := []error{}
 
-	for _, currResource := range resourceList.ExactResources {
+	for i, currResource := range resourceList.ExactResources {
+		currReportPath := reportPath.Child("exactResources").Index(i)
+		report.rule(currReportPath)
 		resourceInstance, err := getExactResource(ctx, dynamicClient, currResource)
 		if apierrors.IsNotFound(err) {
+			report.missingExactResource(currReportPath, currResource, "")
 			continue
 		}
 		if err != nil {
 			errs = append(errs, err)
 			continue
 		}
-		instances.Insert(resourceInstance)
+		keep(currResource.Fields, resourceInstance)
+		report.selectedBy(currReportPath, InputResourceSelection{FieldPath: currReportPath.String()}, resourceInstance)
 	}
 
-	for _, currResource := range resourceList.LabelSelectedResources {
+	for i, currResource := range resourceList.GeneratedNameResources {
+		currReportPath := reportPath.Child("generatedNameResources").Index(i)
+		report.rule(currReportPath)
+		resourceList, err := getResourcesByGeneratedName(ctx, dynamicClient, currResource)
+		if apierrors.IsNotFound(err) || (err == nil && len(resourceList) == 0) {
+			report.missingGeneratedNameResource(currReportPath, currResource)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		keep(nil, resourceList...)
+		report.selectedBy(currReportPath, InputResourceSelection{FieldPath: currReportPath.String(), GeneratedName: currResource.GeneratedName}, resourceList...)
+	}
+
+	// named label selections can be used as the referring side of a resourceReference.
+	labelSelectedByName := map[string]*UniqueResourceSet{}
+	for i, currResource := range resourceList.LabelSelectedResources {
+		currReportPath := reportPath.Child("labelSelectedResources").Index(i)
+		report.rule(currReportPath)
 		resourceList, err := getResourcesByLabelSelector(ctx, dynamicClient, currResource)
-		if apierrors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) || (err == nil && len(resourceList) == 0) {
+			report.missingLabelSelectedResource(currReportPath, currResource)
 			continue
 		}
 		if err != nil {
 			errs = append(errs, err)
 			continue
 		}
-		instances.Insert(resourceList...)
+		keep(currResource.Fields, resourceList...)
+		report.selectedBy(currReportPath, InputResourceSelection{FieldPath: currReportPath.String(), LabelSelector: metav1.FormatLabelSelector(&currResource.LabelSelector)}, resourceList...)
+		if len(currResource.Name) > 0 {
+			labelSelectedByName[currResource.Name] = NewUniqueResourceSet(resourceList...)
+		}
+	}
+
+	for i, currOwnedResource := range resourceList.OwnedResources {
+		currReportPath := reportPath.Child("ownedResources").Index(i)
+		report.rule(currReportPath)
+		ownerInstance, err := getExactResource(ctx, dynamicClient, currOwnedResource.Owner)
+		if apierrors.IsNotFound(err) {
+			report.missingExactResource(currReportPath.Child("owner"), currOwnedResource.Owner, "")
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed reading owner [%v]: %w", currReportPath.Child("owner"), err))
+			continue
+		}
+		keep(currOwnedResource.Owner.Fields, ownerInstance)
+		report.selectedBy(currReportPath.Child("owner"), InputResourceSelection{FieldPath: currReportPath.Child("owner").String()}, ownerInstance)
+
+		ownedResources, err := getOwnedResources(ctx, dynamicClient, currOwnedResource, ownerInstance)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed reading resources owned by %v [%v]: %w", ownerInstance.ID(), currReportPath, err))
+			continue
+		}
+		if len(ownedResources) == 0 {
+			report.missingOwnedResource(currReportPath, currOwnedResource)
+			continue
+		}
+		for _, owned := range ownedResources {
+			keep(nil, owned.resource)
+			report.selectedBy(currReportPath, InputResourceSelection{FieldPath: currReportPath.String(), Owner: owned.owner.ID()}, owned.resource)
+		}
+	}
+
+	// named resourceReferences can be used as the referring side of another resourceReference.
+	referencedByName := map[string]*UniqueResourceSet{}
+	for i, currResourceRef := range resourceList.ResourceReferences {
+		report.rule(reportPath.Child("resourceReferences").Index(i))
+		if len(currResourceRef.Name) > 0 {
+			referencedByName[currResourceRef.Name] = NewUniqueResourceSet()
+		}
 	}
 
 	path := field.NewPath(".")
 	for i, currResourceRef := range resourceList.ResourceReferences {
+		if currResourceRef.ReferringResourcesFrom != nil {
+			continue
+		}
 		currFieldPath := path.Child("resourceReference").Index(i)
+		currReportPath := reportPath.Child("resourceReferences").Index(i)
 
 		referringResourceInstance, err := getExactResource(ctx, dynamicClient, currResourceRef.ReferringResource)
 		if apierrors.IsNotFound(err) {
+			report.missingExactResource(currReportPath.Child("referringResource"), currResourceRef.ReferringResource, "")
 			continue
 		}
 		if err != nil {
 			errs = append(errs, fmt.Errorf("failed reading referringResource [%v] %#v: %w", currFieldPath, currResourceRef.ReferringResource, err))
 			continue
 		}
-		instances.Insert(referringResourceInstance)
-
-		switch {
-		case currResourceRef.ImplicitNamespacedReference != nil:
-			fieldPathEvaluator, err := builder.NewEvaluable(currResourceRef.ImplicitNamespacedReference.NameJSONPath)
-			if err != nil {
-				errs = append(errs, fmt.Errorf("error parsing [%v]: %q: %w", currFieldPath, currResourceRef.ImplicitNamespacedReference.NameJSONPath, err))
-				continue
-			}
+		keep(currResourceRef.ReferringResource.Fields, referringResourceInstance)
+		report.selectedBy(currReportPath.Child("referringResource"), InputResourceSelection{FieldPath: currReportPath.Child("referringResource").String()}, referringResourceInstance)
+
+		referencedResources, referenceErrs := resolveResourceReference(ctx, dynamicClient, currFieldPath, currReportPath, currResourceRef, referringResourceInstance, report)
+		errs = append(errs, referenceErrs...)
+		keep(nil, referencedResources...)
+		if len(currResourceRef.Name) > 0 {
+			referencedByName[currResourceRef.Name].Insert(referencedResources...)
+		}
+	}
 
-			results, err := fieldPathEvaluator(ctx, referringResourceInstance.Content.UnstructuredContent())
-			if err != nil {
-				errs = append(errs, fmt.Errorf("unexpected error finding value for %v from %v with jsonPath: %w", currFieldPath, "TODO", err))
+	// chained references are resolved iteratively until a pass finds no referring resource that hasn't been evaluated.
+	// Tracking what has been evaluated per reference protects against cycles in the content,
+	// like two secrets that refer to each other.
+	evaluatedReferringResources := map[int]sets.Set[string]{}
+	for depth := 0; ; depth++ {
+		foundNewReferringResource := false
+		for i, currResourceRef := range resourceList.ResourceReferences {
+			if currResourceRef.ReferringResourcesFrom == nil {
 				continue
 			}
+			currFieldPath := path.Child("resourceReference").Index(i)
+			currReportPath := reportPath.Child("resourceReferences").Index(i)
 
-			var resultStrings []string
-			switch cast := results.(type) {
-			case string:
-				resultStrings = []string{cast}
-			case []string:
-				resultStrings = cast
-			case []interface{}:
-				for _, curr := range cast {
-					resultStrings = append(resultStrings, fmt.Sprintf("%v", curr))
+			referringResources := NewUniqueResourceSet()
+			for _, name := range currResourceRef.ReferringResourcesFrom.ResourceReferences {
+				if referenced, ok := referencedByName[name]; ok {
+					referringResources.Insert(referenced.List()...)
 				}
-			default:
-				errs = append(errs, fmt.Errorf("[%v] unexpected error type %T for %#v", currFieldPath, results, results))
 			}
-
-			for _, targetResourceName := range resultStrings {
-				targetRef := ExactResourceID{
-					InputResourceTypeIdentifier: currResourceRef.ImplicitNamespacedReference.InputResourceTypeIdentifier,
-					Namespace:                   currResourceRef.ImplicitNamespacedReference.Namespace,
-					Name:                        targetResourceName,
+			for _, name := range currResourceRef.ReferringResourcesFrom.LabelSelectedResources {
+				if selected, ok := labelSelectedByName[name]; ok {
+					referringResources.Insert(selected.List()...)
 				}
+			}
 
-				resourceInstance, err := getExactResource(ctx, dynamicClient, targetRef)
-				if apierrors.IsNotFound(err) {
+			if _, ok := evaluatedReferringResources[i]; !ok {
+				evaluatedReferringResources[i] = sets.New[string]()
+			}
+			for _, referringResourceInstance := range referringResources.List() {
+				if evaluatedReferringResources[i].Has(referringResourceInstance.ID()) {
 					continue
 				}
-				if err != nil {
-					errs = append(errs, err)
+				if depth >= maxReferenceChainDepth {
+					errs = append(errs, fmt.Errorf("[%v] reference chain is longer than %d, not following %v", currFieldPath, maxReferenceChainDepth, referringResourceInstance.ID()))
+					evaluatedReferringResources[i].Insert(referringResourceInstance.ID())
 					continue
 				}
-
-				instances.Insert(resourceInstance)
+				foundNewReferringResource = true
+				evaluatedReferringResources[i].Insert(referringResourceInstance.ID())
+
+				referencedResources, referenceErrs := resolveResourceReference(ctx, dynamicClient, currFieldPath, currReportPath, currResourceRef, referringResourceInstance, report)
+				errs = append(errs, referenceErrs...)
+				keep(nil, referencedResources...)
+				if len(currResourceRef.Name) > 0 {
+					referencedByName[currResourceRef.Name].Insert(referencedResources...)
+				}
 			}
 		}
+		if !foundNewReferringResource {
+			break
+		}
 	}
 
-	return instances.List(), errors.Join(errs...)
+	// references are evaluated against whole resources, projection only happens on the way out.
+	projected, err := projections.project(instances.List())
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return projected, errors.Join(errs...)
+}
+
+// resolveResourceReference evaluates a single resourceReference against one referring resource and reads every resource it refers to.
+// Referenced resources that do not exist are skipped and added to the report.
+func resolveResourceReference(ctx context.Context, dynamicClient dynamic.Interface, fieldPath, reportPath *field.Path, resourceRef ResourceReference, referringResourceInstance *Resource, report *resolutionReport) ([]*Resource, []error) {
+	targetRefs := []ExactResourceID{}
+	targetMatches := [][]ReferenceMatch{}
+	switch {
+	case resourceRef.ImplicitNamespacedReference != nil:
+		referencePath := fieldPath.Child("implicitNamespacedReference")
+		nameMatch := newReferenceMatch(reportPath.Child("implicitNamespacedReference"), "name", resourceRef.ImplicitNamespacedReference.NameJSONPath, resourceRef.ImplicitNamespacedReference.NameExpression)
+		names, err := evaluateReferenceValues(ctx, referencePath, "name", resourceRef.ImplicitNamespacedReference.NameJSONPath, resourceRef.ImplicitNamespacedReference.NameExpression, referringResourceInstance)
+		if err != nil {
+			return nil, []error{err}
+		}
+
+		for _, targetResourceName := range names {
+			targetRefs = append(targetRefs, ExactResourceID{
+				InputResourceTypeIdentifier: resourceRef.ImplicitNamespacedReference.InputResourceTypeIdentifier,
+				Namespace:                   resourceRef.ImplicitNamespacedReference.Namespace,
+				Name:                        targetResourceName,
+			})
+			targetMatches = append(targetMatches, []ReferenceMatch{nameMatch.withValue(targetResourceName)})
+		}
+
+	case resourceRef.ExplicitNamespacedReference != nil:
+		referencePath := fieldPath.Child("explicitNamespacedReference")
+		namespaceMatch := newReferenceMatch(reportPath.Child("explicitNamespacedReference"), "namespace", resourceRef.ExplicitNamespacedReference.NamespaceJSONPath, resourceRef.ExplicitNamespacedReference.NamespaceExpression)
+		nameMatch := newReferenceMatch(reportPath.Child("explicitNamespacedReference"), "name", resourceRef.ExplicitNamespacedReference.NameJSONPath, resourceRef.ExplicitNamespacedReference.NameExpression)
+		namespaces, err := evaluateReferenceValues(ctx, referencePath, "namespace", resourceRef.ExplicitNamespacedReference.NamespaceJSONPath, resourceRef.ExplicitNamespacedReference.NamespaceExpression, referringResourceInstance)
+		if err != nil {
+			return nil, []error{err}
+		}
+		names, err := evaluateReferenceValues(ctx, referencePath, "name", resourceRef.ExplicitNamespacedReference.NameJSONPath, resourceRef.ExplicitNamespacedReference.NameExpression, referringResourceInstance)
+		if err != nil {
+			return nil, []error{err}
+		}
+		// the namespace and name results are paired by index, so they must line up exactly.
+		if len(namespaces) != len(names) {
+			return nil, []error{fmt.Errorf("[%v] namespace produced %d results and name produced %d results, they must match", fieldPath, len(namespaces), len(names))}
+		}
+
+		for i := range names {
+			targetRefs = append(targetRefs, ExactResourceID{
+				InputResourceTypeIdentifier: resourceRef.ExplicitNamespacedReference.InputResourceTypeIdentifier,
+				Namespace:                   namespaces[i],
+				Name:                        names[i],
+			})
+			targetMatches = append(targetMatches, []ReferenceMatch{namespaceMatch.withValue(namespaces[i]), nameMatch.withValue(names[i])})
+		}
+
+	case resourceRef.ClusterScopedReference != nil:
+		referencePath := fieldPath.Child("clusterScopedReference")
+		nameMatch := newReferenceMatch(reportPath.Child("clusterScopedReference"), "name", resourceRef.ClusterScopedReference.NameJSONPath, resourceRef.ClusterScopedReference.NameExpression)
+		names, err := evaluateReferenceValues(ctx, referencePath, "name", resourceRef.ClusterScopedReference.NameJSONPath, resourceRef.ClusterScopedReference.NameExpression, referringResourceInstance)
+		if err != nil {
+			return nil, []error{err}
+		}
+
+		for _, targetResourceName := range names {
+			targetRefs = append(targetRefs, ExactResourceID{
+				InputResourceTypeIdentifier: resourceRef.ClusterScopedReference.InputResourceTypeIdentifier,
+				Name:                        targetResourceName,
+			})
+			targetMatches = append(targetMatches, []ReferenceMatch{nameMatch.withValue(targetResourceName)})
+		}
+	}
+
+	ret := []*Resource{}
+	errs := []error{}
+	for i, targetRef := range targetRefs {
+		resourceInstance, err := getExactResource(ctx, dynamicClient, targetRef)
+		if apierrors.IsNotFound(err) {
+			report.missingExactResource(reportPath, targetRef, referringResourceInstance.ID())
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		ret = append(ret, resourceInstance)
+		report.selectedBy(reportPath, InputResourceSelection{
+			FieldPath:         reportPath.String(),
+			ReferringResource: referringResourceInstance.ID(),
+			Matches:           targetMatches[i],
+		}, resourceInstance)
+	}
+
+	return ret, errs
+}
+
+// newReferenceMatch describes the <prefix>Expression or <prefix>JSONPath used to find a value, without the value.
+func newReferenceMatch(fieldPath *field.Path, prefix, jsonPath, expression string) ReferenceMatch {
+	if len(expression) > 0 {
+		return ReferenceMatch{FieldPath: fieldPath.Child(prefix + "Expression").String(), Query: expression}
+	}
+	return ReferenceMatch{FieldPath: fieldPath.Child(prefix + "JSONPath").String(), Query: jsonPath}
+}
+
+func (m ReferenceMatch) withValue(value string) ReferenceMatch {
+	m.Value = value
+	return m
+}
+
+// evaluateReferenceValues evaluates either the <prefix>Expression (CEL) or the <prefix>JSONPath against the referringResource.
+// Validation ensures that only one is set.
+func evaluateReferenceValues(ctx context.Context, fieldPath *field.Path, prefix, jsonPath, expression string, referringResource *Resource) ([]string, error) {
+	if len(expression) > 0 {
+		return evaluateCELExpression(ctx, fieldPath.Child(prefix+"Expression"), expression, referringResource)
+	}
+	return evaluateJSONPath(ctx, fieldPath.Child(prefix+"JSONPath"), jsonPath, referringResource)
+}
+
+// evaluateJSONPath runs the jsonPath against the referringResource and returns every match as a string.
+func evaluateJSONPath(ctx context.Context, fieldPath *field.Path, jsonPath string, referringResource *Resource) ([]string, error) {
+	fieldPathEvaluator, err := builder.NewEvaluable(jsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing [%v]: %q: %w", fieldPath, jsonPath, err)
+	}
+
+	results, err := fieldPathEvaluator(ctx, referringResource.Content.UnstructuredContent())
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error finding value for %v from %v with jsonPath: %w", fieldPath, referringResource.ID(), err)
+	}
+
+	var resultStrings []string
+	switch cast := results.(type) {
+	case string:
+		resultStrings = []string{cast}
+	case []string:
+		resultStrings = cast
+	case []interface{}:
+		for _, curr := range cast {
+			resultStrings = append(resultStrings, fmt.Sprintf("%v", curr))
+		}
+	default:
+		return nil, fmt.Errorf("[%v] unexpected error type %T for %#v", fieldPath, results, results)
+	}
+
+	return resultStrings, nil
 }
 
 func getExactResource(ctx context.Context, dynamicClient dynamic.Interface, resourceReference ExactResourceID) (*Resource, error) {
@@ -215,6 +606,10 @@ func getResourcesByLabelSelector(ctx context.Context, dynamicClient dynamic.Inte
 
 	var resources []*Resource
 	for _, item := range unstructuredList.Items {
+		// not every client honors the labelSelector on list, so check it here too.
+		if !selector.Matches(labels.Set(item.GetLabels())) {
+			continue
+		}
 		resourceInstance := &Resource{
 			ResourceType: gvr,
 			Content:      &item,
@@ -225,10 +620,46 @@ func getResourcesByLabelSelector(ctx context.Context, dynamicClient dynamic.Inte
 	return resources, nil
 }
 
+// getResourcesByGeneratedName lists resources and keeps only those created with a matching generateName.
+// Like the output filter, this is not a cheat code for prefix matching: resources without a generateName never match.
+func getResourcesByGeneratedName(ctx context.Context, dynamicClient dynamic.Interface, generatedResource GeneratedResourceID) ([]*Resource, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    generatedResource.Group,
+		Version:  generatedResource.Version,
+		Resource: generatedResource.Resource,
+	}
+
+	unstructuredList, err := dynamicClient.Resource(gvr).Namespace(generatedResource.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed getting list of resources with generateName %q: %w", generatedResource.GeneratedName, err)
+	}
+
+	var resources []*Resource
+	for i := range unstructuredList.Items {
+		item := unstructuredList.Items[i]
+		if item.GetGenerateName() != generatedResource.GeneratedName {
+			continue
+		}
+		if !strings.HasPrefix(item.GetName(), generatedResource.GeneratedName) {
+			continue
+		}
+		resources = append(resources, &Resource{
+			ResourceType: gvr,
+			Content:      &item,
+		})
+	}
+
+	return resources, nil
+}
+
 func IdentifierForExactResourceRef(resourceReference *ExactResourceID) string {
 	return fmt.Sprintf("%s.%s.%s/%s[%s]", resourceReference.Resource, resourceReference.Version, resourceReference.Group, resourceReference.Name, resourceReference.Namespace)
 }
 
+// unstructuredToMustGatherFormat groups resources into the list files used by must-gather.
+// A list file can only hold a single version, so when a GroupKind is present at more than one version each resource
+// is written to its own file instead.  The manifestclient reader understands both forms.  If the same resource is present
+// at more than one version, only the most preferred version is kept, just as an API server would store it once.
 func unstructuredToMustGatherFormat(in []*Resource) ([]*Resource, error) {
 	type mustGatherKeyType struct {
 		gk        schema.GroupKind
@@ -236,17 +667,35 @@ func unstructuredToMustGatherFormat(in []*Resource) ([]*Resource, error) {
 	}
 
 	versionsByGroupKind := map[schema.GroupKind]sets.Set[string]{}
-	groupKindToResource := map[schema.GroupKind]schema.GroupVersionResource{}
-	byGroupKind := map[mustGatherKeyType]*unstructured.UnstructuredList{}
 	for _, curr := range in {
 		gvk := curr.Content.GroupVersionKind()
-		groupKind := curr.Content.GroupVersionKind().GroupKind()
-		existingVersions, ok := versionsByGroupKind[groupKind]
+		existingVersions, ok := versionsByGroupKind[gvk.GroupKind()]
 		if !ok {
 			existingVersions = sets.New[string]()
-			versionsByGroupKind[groupKind] = existingVersions
+			versionsByGroupKind[gvk.GroupKind()] = existingVersions
 		}
 		existingVersions.Insert(gvk.Version)
+	}
+
+	ret := []*Resource{}
+	groupKindToResource := map[schema.GroupKind]schema.GroupVersionResource{}
+	byGroupKind := map[mustGatherKeyType]*unstructured.UnstructuredList{}
+	individualResources := map[string]*Resource{}
+	for _, curr := range in {
+		groupKind := curr.Content.GroupVersionKind().GroupKind()
+		if versionsByGroupKind[groupKind].Len() > 1 {
+			individualFilename := individualMustGatherFilename(curr)
+			existing, ok := individualResources[individualFilename]
+			if ok && version.CompareKubeAwareVersionStrings(existing.ResourceType.Version, curr.ResourceType.Version) >= 0 {
+				continue
+			}
+			individualResources[individualFilename] = &Resource{
+				Filename:     individualFilename,
+				Content:      curr.Content.DeepCopy(),
+				ResourceType: curr.ResourceType,
+			}
+			continue
+		}
 		groupKindToResource[groupKind] = curr.ResourceType
 
 		mustGatherKey := mustGatherKeyType{
@@ -264,35 +713,15 @@ func unstructuredToMustGatherFormat(in []*Resource) ([]*Resource, error) {
 		}
 		existing.Items = append(existing.Items, *curr.Content.DeepCopy())
 	}
-
-	errs := []error{}
-	for groupKind, currVersions := range versionsByGroupKind {
-		if len(currVersions) == 1 {
-			continue
-		}
-		errs = append(errs, fmt.Errorf("groupKind=%v has multiple versions: %v, which prevents serialization", groupKind, sets.List(currVersions)))
-	}
-	if len(errs) > 0 {
-		return nil, errors.Join(errs...)
+	for _, individualFilename := range sets.List(sets.KeySet(individualResources)) {
+		ret = append(ret, individualResources[individualFilename])
 	}
 
-	ret := []*Resource{}
 	for mustGatherKey, list := range byGroupKind {
-		namespacedString := "REPLACE_ME"
-		if len(mustGatherKey.namespace) > 0 {
-			namespacedString = "namespaces"
-		} else {
-			namespacedString = "cluster-scoped-resources"
-		}
-
-		groupString := mustGatherKey.gk.Group
-		if len(groupString) == 0 {
-			groupString = "core"
-		}
 		listAsUnstructured := &unstructured.Unstructured{Object: list.UnstructuredContent()}
 		resourceType := groupKindToResource[mustGatherKey.gk]
 		ret = append(ret, &Resource{
-			Filename:     path.Join(namespacedString, mustGatherKey.namespace, groupString, fmt.Sprintf("%s.yaml", resourceType.Resource)),
+			Filename:     path.Join(mustGatherDirectory(mustGatherKey.gk.Group, mustGatherKey.namespace), fmt.Sprintf("%s.yaml", resourceType.Resource)),
 			Content:      listAsUnstructured,
 			ResourceType: resourceType,
 		})
@@ -301,6 +730,23 @@ func unstructuredToMustGatherFormat(in []*Resource) ([]*Resource, error) {
 	return ret, nil
 }
 
+// mustGatherDirectory is the directory holding resources for a group in a namespace, or cluster-scoped if namespace is empty.
+func mustGatherDirectory(group, namespace string) string {
+	groupString := group
+	if len(groupString) == 0 {
+		groupString = "core"
+	}
+	if len(namespace) > 0 {
+		return path.Join("namespaces", namespace, groupString)
+	}
+	return path.Join("cluster-scoped-resources", groupString)
+}
+
+// individualMustGatherFilename is the location of a resource stored in its own file.
+func individualMustGatherFilename(in *Resource) string {
+	return path.Join(mustGatherDirectory(in.ResourceType.Group, in.Content.GetNamespace()), in.ResourceType.Resource, fmt.Sprintf("%s.yaml", in.Content.GetName()))
+}
+
 func guessListKind(in *unstructured.Unstructured) schema.GroupVersionKind {
 	return schema.GroupVersionKind{
 		Group:   in.GroupVersionKind().Group,
@@ -2,15 +2,23 @@ package libraryinputresources
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
+	"reflect"
 	"strings"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/yaml"
 )
 
@@ -40,25 +48,31 @@ func TestGetRequiredResourcesFromMustGather(t *testing.T) {
 			mustGatherDirPath := path.Join("test-data", currTestDir.Name(), "input-dir")
 			expectedDirPath := path.Join("test-data", currTestDir.Name(), "expected-output")
 
+			dynamicClient, err := NewDynamicClientFromMustGather(mustGatherDirPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actualPertinentResources, _, err := GetRequiredInputAndOperandResourcesFromClientWithReport(ctx, pertinentResources, dynamicClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+
 			if writeActualContent {
-				err = WriteRequiredInputResourcesFromMustGather(ctx, pertinentResources, mustGatherDirPath, expectedDirPath)
-				if err != nil {
+				if err := WriteResources(actualPertinentResources, expectedDirPath); err != nil {
 					t.Fatal(err)
 				}
 				return
 			}
 
-			actualPertinentResources, err := GetRequiredInputResourcesFromMustGather(ctx, pertinentResources, mustGatherDirPath)
+			expectedPertinentResources, warnings, err := LenientResourcesFromDirRecursive(expectedDirPath)
 			if err != nil {
 				t.Fatal(err)
 			}
-
-			expectedPertinentResources, err := LenientResourcesFromDirRecursive(expectedDirPath)
-			if err != nil {
-				t.Fatal(err)
+			if len(warnings) > 0 {
+				t.Fatalf("unexpected warnings: %v", warnings)
 			}
 
-			expectedPertinentResources, err = unstructuredToMustGatherFormat(expectedPertinentResources)
+			expectedPertinentResources, err = mustGatherFormatByDirectory(expectedPertinentResources)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -72,6 +86,383 @@ func TestGetRequiredResourcesFromMustGather(t *testing.T) {
 	}
 }
 
+// mustGatherFormatByDirectory formats the root and each operand directory separately, the way they are produced.
+func mustGatherFormatByDirectory(in []*Resource) ([]*Resource, error) {
+	byDirectory := map[string][]*Resource{}
+	for _, curr := range in {
+		directory, _, _ := strings.Cut(curr.Filename, "/")
+		switch directory {
+		case ConfigurationOperandDirectory, ManagementOperandDirectory, UserWorkloadOperandDirectory:
+		default:
+			directory = ""
+		}
+		byDirectory[directory] = append(byDirectory[directory], curr)
+	}
+
+	ret := []*Resource{}
+	for directory, resources := range byDirectory {
+		formatted, err := unstructuredToMustGatherFormat(resources)
+		if err != nil {
+			return nil, err
+		}
+		for _, curr := range formatted {
+			curr.Filename = path.Join(directory, curr.Filename)
+		}
+		ret = append(ret, formatted...)
+	}
+	return ret, nil
+}
+
+func TestWriteRequiredInputResourcesFromClient(t *testing.T) {
+	testDir := path.Join("test-data", "explicit-references-01")
+	pertinentResourcesBytes, err := os.ReadFile(path.Join(testDir, "input-resources.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pertinentResources := &InputResources{}
+	if err := yaml.Unmarshal(pertinentResourcesBytes, &pertinentResources); err != nil {
+		t.Fatal(err)
+	}
+
+	// stand in for a live kube-apiserver by serving the must-gather over http.
+	mustGatherClient := newHTTPClientFromFS(os.DirFS(path.Join(testDir, "input-dir")))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		req.RequestURI = ""
+		req.URL.Scheme = "https"
+		req.URL.Host = "must-gather"
+		resp, err := mustGatherClient.Transport.RoundTrip(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer resp.Body.Close()
+		for k, v := range resp.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	defer server.Close()
+
+	dynamicClient, err := dynamic.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputDir := t.TempDir()
+	if err := WriteRequiredInputResourcesFromClient(context.Background(), pertinentResources, dynamicClient, outputDir); err != nil {
+		t.Fatal(err)
+	}
+
+	actualResources, warnings, err := LenientResourcesFromDirRecursive(outputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) > 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	expectedResources, warnings, err := LenientResourcesFromDirRecursive(path.Join(testDir, "expected-output"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) > 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	differences := EquivalentResources("written", expectedResources, actualResources)
+	if len(differences) > 0 {
+		t.Log(strings.Join(differences, "\n"))
+		t.Errorf("expected results mismatch %d times with actual results", len(differences))
+	}
+	if len(expectedResources) != len(actualResources) {
+		t.Errorf("expected %d resources, got %d", len(expectedResources), len(actualResources))
+	}
+}
+
+func TestGetRequiredInputResourcesFromClientWithReport(t *testing.T) {
+	oauthType := InputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1", Resource: "oauths"}
+	infrastructureType := InputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1", Resource: "infrastructures"}
+	relatedObjectsExpression := func(resource string) string {
+		return fmt.Sprintf(`object.status.relatedObjects.filter(r, r.resource == "%s").map(r, r.name)`, resource)
+	}
+	missingClusterOperator := ExactClusterOperator("missing")
+	missingCSRs := GeneratedCSR("csr-")
+	noClusterOperators := LabelSelectedResource{
+		InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"},
+		LabelSelector:               metav1.LabelSelector{MatchLabels: map[string]string{"app": "nothing"}},
+	}
+
+	inputResources := &InputResources{
+		ApplyConfigurationResources: ResourceList{
+			ExactResources: []ExactResourceID{
+				ExactClusterOperator("authentication"),
+				missingClusterOperator,
+			},
+			GeneratedNameResources: []GeneratedResourceID{missingCSRs},
+			LabelSelectedResources: []LabelSelectedResource{noClusterOperators},
+			ResourceReferences: []ResourceReference{
+				{
+					ReferringResource: ExactClusterOperator("authentication"),
+					Type:              ClusterScopedReferenceType,
+					ClusterScopedReference: &ClusterScopedReference{
+						InputResourceTypeIdentifier: oauthType,
+						NameExpression:              relatedObjectsExpression("oauths"),
+					},
+				},
+				{
+					ReferringResource: ExactClusterOperator("authentication"),
+					Type:              ClusterScopedReferenceType,
+					ClusterScopedReference: &ClusterScopedReference{
+						InputResourceTypeIdentifier: infrastructureType,
+						NameExpression:              relatedObjectsExpression("infrastructures"),
+					},
+				},
+				{
+					ReferringResource: missingClusterOperator,
+					Type:              ClusterScopedReferenceType,
+					ClusterScopedReference: &ClusterScopedReference{
+						InputResourceTypeIdentifier: oauthType,
+						NameExpression:              relatedObjectsExpression("oauths"),
+					},
+				},
+			},
+		},
+	}
+
+	dynamicClient, err := NewDynamicClientFromMustGather(path.Join("test-data", "cluster-scoped-references-01", "input-dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, report, err := GetRequiredInputResourcesFromClientWithReport(context.Background(), inputResources, dynamicClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMissing := &MissingInputResources{
+		MissingResources: []MissingInputResource{
+			{
+				FieldPath:     "applyConfigurationResources.exactResources[1]",
+				ExactResource: &missingClusterOperator,
+			},
+			{
+				FieldPath:             "applyConfigurationResources.generatedNameResources[0]",
+				GeneratedNameResource: &missingCSRs,
+			},
+			{
+				FieldPath:             "applyConfigurationResources.labelSelectedResources[0]",
+				LabelSelectedResource: &noClusterOperators,
+			},
+			{
+				FieldPath:         "applyConfigurationResources.resourceReferences[1]",
+				ExactResource:     &ExactResourceID{InputResourceTypeIdentifier: infrastructureType, Name: "cluster"},
+				ReferringResource: "config.openshift.io/clusteroperators/_cluster_scoped_resource_/authentication",
+			},
+			{
+				FieldPath:     "applyConfigurationResources.resourceReferences[2].referringResource",
+				ExactResource: &missingClusterOperator,
+			},
+		},
+	}
+	if !equality.Semantic.DeepEqual(expectedMissing, report.Missing) {
+		t.Error(diff.ObjectDiff(expectedMissing, report.Missing))
+	}
+
+	expectedExplained := &ExplainedInputResources{
+		Resources: []ExplainedInputResource{
+			{
+				Resource: ExactResourceID{InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"}, Name: "authentication"},
+				SelectedBy: []InputResourceSelection{
+					{FieldPath: "applyConfigurationResources.exactResources[0]"},
+					{FieldPath: "applyConfigurationResources.resourceReferences[0].referringResource"},
+					{FieldPath: "applyConfigurationResources.resourceReferences[1].referringResource"},
+				},
+			},
+			{
+				Resource: ExactResourceID{InputResourceTypeIdentifier: oauthType, Name: "cluster"},
+				SelectedBy: []InputResourceSelection{
+					{
+						FieldPath:         "applyConfigurationResources.resourceReferences[0]",
+						ReferringResource: "config.openshift.io/clusteroperators/_cluster_scoped_resource_/authentication",
+						Matches: []ReferenceMatch{
+							{
+								FieldPath: "applyConfigurationResources.resourceReferences[0].clusterScopedReference.nameExpression",
+								Query:     relatedObjectsExpression("oauths"),
+								Value:     "cluster",
+							},
+						},
+					},
+				},
+			},
+		},
+		RulesSelectingNothing: []string{
+			"applyConfigurationResources.exactResources[1]",
+			"applyConfigurationResources.generatedNameResources[0]",
+			"applyConfigurationResources.labelSelectedResources[0]",
+			"applyConfigurationResources.resourceReferences[1]",
+			"applyConfigurationResources.resourceReferences[2]",
+		},
+	}
+	if !equality.Semantic.DeepEqual(expectedExplained, report.Explained) {
+		t.Error(diff.ObjectDiff(expectedExplained, report.Explained))
+	}
+}
+
+func TestGetRequiredInputResourcesForResourceListErrors(t *testing.T) {
+	authenticationClusterOperator := ExactClusterOperator("authentication")
+	oauthType := InputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1", Resource: "oauths"}
+
+	testCases := []struct {
+		name          string
+		resourceList  ResourceList
+		expectedError string
+	}{
+		{
+			name: "cluster scoped reference to an object",
+			resourceList: ResourceList{
+				ResourceReferences: []ResourceReference{
+					{
+						ReferringResource: authenticationClusterOperator,
+						Type:              ClusterScopedReferenceType,
+						ClusterScopedReference: &ClusterScopedReference{
+							InputResourceTypeIdentifier: oauthType,
+							NameJSONPath:                "$.status.relatedObjects[0]",
+						},
+					},
+				},
+			},
+			expectedError: "[..resourceReference[0].clusterScopedReference.nameJSONPath] unexpected error type map[string]interface {}",
+		},
+		{
+			name: "cluster scoped reference that doesn't parse",
+			resourceList: ResourceList{
+				ResourceReferences: []ResourceReference{
+					{
+						ReferringResource: authenticationClusterOperator,
+						Type:              ClusterScopedReferenceType,
+						ClusterScopedReference: &ClusterScopedReference{
+							InputResourceTypeIdentifier: oauthType,
+							NameJSONPath:                "please DON'T compile[AND foo]",
+						},
+					},
+				},
+			},
+			expectedError: "error parsing [..resourceReference[0].clusterScopedReference.nameJSONPath]",
+		},
+		{
+			name: "explicit reference with mismatched namespaces and names",
+			resourceList: ResourceList{
+				ResourceReferences: []ResourceReference{
+					{
+						ReferringResource: authenticationClusterOperator,
+						Type:              ExplicitNamespacedReferenceType,
+						ExplicitNamespacedReference: &ExplicitNamespacedReference{
+							InputResourceTypeIdentifier: InputResourceTypeIdentifier{Group: "route.openshift.io", Version: "v1", Resource: "routes"},
+							NamespaceJSONPath:           "$.status.relatedObjects[*].namespace",
+							NameJSONPath:                "$.status.relatedObjects[*].name",
+						},
+					},
+				},
+			},
+			expectedError: "namespace produced 2 results and name produced 12 results, they must match",
+		},
+		{
+			name: "cluster scoped reference with a cel expression producing non-strings",
+			resourceList: ResourceList{
+				ResourceReferences: []ResourceReference{
+					{
+						ReferringResource: authenticationClusterOperator,
+						Type:              ClusterScopedReferenceType,
+						ClusterScopedReference: &ClusterScopedReference{
+							InputResourceTypeIdentifier: oauthType,
+							NameExpression:              "object.status.relatedObjects",
+						},
+					},
+				},
+			},
+			expectedError: "[..resourceReference[0].clusterScopedReference.nameExpression] expression must produce a list of strings",
+		},
+		{
+			name: "cluster scoped reference with a cel expression referencing a missing field",
+			resourceList: ResourceList{
+				ResourceReferences: []ResourceReference{
+					{
+						ReferringResource: authenticationClusterOperator,
+						Type:              ClusterScopedReferenceType,
+						ClusterScopedReference: &ClusterScopedReference{
+							InputResourceTypeIdentifier: oauthType,
+							NameExpression:              "object.spec.missing",
+						},
+					},
+				},
+			},
+			expectedError: "unexpected error finding value for ..resourceReference[0].clusterScopedReference.nameExpression from config.openshift.io/clusteroperators/_cluster_scoped_resource_/authentication with expression: no such key: missing",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dynamicClient, err := NewDynamicClientFromMustGather(path.Join("test-data", "cluster-scoped-references-01", "input-dir"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = GetRequiredInputResourcesForResourceList(context.Background(), tc.resourceList, dynamicClient)
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.expectedError)
+			}
+			if !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestGetRequiredInputResourcesForResourceListChainDepth(t *testing.T) {
+	linkedSecretExpression := `has(object.metadata.annotations) && "example.openshift.io/linked-secret" in object.metadata.annotations ? [object.metadata.annotations["example.openshift.io/linked-secret"]] : []`
+	resourceList := ResourceList{
+		ResourceReferences: []ResourceReference{
+			{
+				Name:              "start",
+				ReferringResource: ExactSecret("openshift-config", "chain-00"),
+				Type:              ImplicitNamespacedReferenceType,
+				ImplicitNamespacedReference: &ImplicitNamespacedReference{
+					InputResourceTypeIdentifier: SecretIdentifierType(),
+					Namespace:                   "openshift-config",
+					NameExpression:              linkedSecretExpression,
+				},
+			},
+			{
+				Name: "chain",
+				ReferringResourcesFrom: &ReferringResourcesFrom{
+					ResourceReferences: []string{"start", "chain"},
+				},
+				Type: ImplicitNamespacedReferenceType,
+				ImplicitNamespacedReference: &ImplicitNamespacedReference{
+					InputResourceTypeIdentifier: SecretIdentifierType(),
+					Namespace:                   "openshift-config",
+					NameExpression:              linkedSecretExpression,
+				},
+			},
+		},
+	}
+
+	dynamicClient, err := NewDynamicClientFromMustGather(path.Join("test-data", "chained-references-01", "input-dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := GetRequiredInputResourcesForResourceList(context.Background(), resourceList, dynamicClient)
+	if err == nil {
+		t.Fatal("expected error for exceeding the chain depth, got nil")
+	}
+	if expected := "[..resourceReference[1]] reference chain is longer than 10, not following /secrets/openshift-config/chain-11"; !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected error containing %q, got %v", expected, err)
+	}
+	// chain-00 is the referringResource, chain-01 comes from start, then ten passes of chain reach chain-11.
+	if len(actual) != 12 {
+		t.Errorf("expected 12 resources, got %d", len(actual))
+	}
+}
+
 func TestEnsureResourceType(t *testing.T) {
 	content, err := os.ReadDir("test-data")
 	if err != nil {
@@ -83,10 +474,13 @@ func TestEnsureResourceType(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			mustGatherDirPath := path.Join("test-data", currTestDir.Name(), "input-dir")
 
-			inputDirResources, err := LenientResourcesFromDirRecursive(mustGatherDirPath)
+			inputDirResources, warnings, err := LenientResourcesFromDirRecursive(mustGatherDirPath)
 			if err != nil {
 				t.Fatal(err)
 			}
+			if len(warnings) > 0 {
+				t.Fatalf("unexpected warnings: %v", warnings)
+			}
 
 			for _, resource := range inputDirResources {
 				if resource.ResourceType.Resource == "" {
@@ -97,6 +491,44 @@ func TestEnsureResourceType(t *testing.T) {
 	}
 }
 
+func TestUnstructuredToMustGatherFormatMultipleVersions(t *testing.T) {
+	policy := func(version, name string) *Resource {
+		content := &unstructured.Unstructured{}
+		content.SetAPIVersion("admissionregistration.k8s.io/" + version)
+		content.SetKind("ValidatingAdmissionPolicy")
+		content.SetName(name)
+		return &Resource{
+			ResourceType: schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: version, Resource: "validatingadmissionpolicies"},
+			Content:      content,
+		}
+	}
+
+	// a live cluster serves the same policy at both versions, the preferred version wins regardless of order.
+	actual, err := unstructuredToMustGatherFormat([]*Resource{
+		policy("v1", "both"),
+		policy("v1beta1", "both"),
+		policy("v1beta1", "beta-only"),
+		policy("v1beta1", "both-reversed"),
+		policy("v1", "both-reversed"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"cluster-scoped-resources/admissionregistration.k8s.io/validatingadmissionpolicies/beta-only.yaml":     "admissionregistration.k8s.io/v1beta1",
+		"cluster-scoped-resources/admissionregistration.k8s.io/validatingadmissionpolicies/both.yaml":          "admissionregistration.k8s.io/v1",
+		"cluster-scoped-resources/admissionregistration.k8s.io/validatingadmissionpolicies/both-reversed.yaml": "admissionregistration.k8s.io/v1",
+	}
+	actualFiles := map[string]string{}
+	for _, curr := range actual {
+		actualFiles[curr.Filename] = curr.Content.GetAPIVersion()
+	}
+	if !equality.Semantic.DeepEqual(expected, actualFiles) {
+		t.Error(diff.ObjectDiff(expected, actualFiles))
+	}
+}
+
 func TestUniqueResourceSet(t *testing.T) {
 	name1 := "audit"
 	name2 := "audit-revision-1"
@@ -205,3 +637,28 @@ func makeResourcesWithNames(namespace string, names ...string) []*Resource {
 	}
 	return resources
 }
+
+func TestGetRequiredInputResourcesFromMustGatherSkipsOperands(t *testing.T) {
+	testDir := path.Join("test-data", "operand-resources-01")
+	pertinentResourcesBytes, err := os.ReadFile(path.Join(testDir, "input-resources.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pertinentResources := &InputResources{}
+	if err := yaml.Unmarshal(pertinentResourcesBytes, &pertinentResources); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := GetRequiredInputResourcesFromMustGather(context.Background(), pertinentResources, path.Join(testDir, "input-dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actualFilenames := []string{}
+	for _, curr := range actual {
+		actualFilenames = append(actualFilenames, curr.Filename)
+	}
+	expectedFilenames := []string{"cluster-scoped-resources/config.openshift.io/apiservers.yaml"}
+	if !reflect.DeepEqual(expectedFilenames, actualFilenames) {
+		t.Errorf("expected %v, got %v", expectedFilenames, actualFilenames)
+	}
+}
@@ -0,0 +1,199 @@
+package libraryinputresources
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// InputResourcesReport describes how an InputResources was resolved against a cluster or must-gather.
+type InputResourcesReport struct {
+	Missing   *MissingInputResources
+	Explained *ExplainedInputResources
+}
+
+// MissingInputResources lists every resource that InputResources asked for, but that was not present.
+// An incomplete test input and an input that was never requested look the same in a pruned must-gather, this makes
+// the difference visible.
+type MissingInputResources struct {
+	MissingResources []MissingInputResource `json:"missingResources"`
+}
+
+type MissingInputResource struct {
+	// FieldPath is the path to the rule in the InputResources that requested the resource.
+	FieldPath string `json:"fieldPath"`
+
+	// ExactResource is set when a single resource was requested by exactResources, a referringResource, or as the
+	// target of a resourceReference.
+	ExactResource *ExactResourceID `json:"exactResource,omitempty"`
+	// ReferringResource is the resource whose content produced the ExactResource for a resourceReference.
+	ReferringResource string `json:"referringResource,omitempty"`
+
+	// GeneratedNameResource is set when no resource was created with the generateName.
+	GeneratedNameResource *GeneratedResourceID `json:"generatedNameResource,omitempty"`
+
+	// LabelSelectedResource is set when the label selection found no resources.
+	LabelSelectedResource *LabelSelectedResource `json:"labelSelectedResource,omitempty"`
+
+	// OwnedResource is set when the owner exists, but owns no resources of the ownedResourceTypes.
+	OwnedResource *OwnedResource `json:"ownedResource,omitempty"`
+}
+
+// ExplainedInputResources records why every selected resource was selected.  Reviewers of test fixtures can use it to
+// see which rule pulled a resource into the input-dir.
+type ExplainedInputResources struct {
+	Resources []ExplainedInputResource `json:"resources"`
+	// RulesSelectingNothing are the field paths of rules that did not select any resource.
+	RulesSelectingNothing []string `json:"rulesSelectingNothing"`
+}
+
+type ExplainedInputResource struct {
+	Resource   ExactResourceID          `json:"resource"`
+	SelectedBy []InputResourceSelection `json:"selectedBy"`
+}
+
+type InputResourceSelection struct {
+	// FieldPath is the path to the rule in the InputResources that selected the resource.
+	FieldPath string `json:"fieldPath"`
+
+	// LabelSelector is set when the resource was selected by labelSelectedResources.
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// GeneratedName is set when the resource was selected by generatedNameResources.
+	GeneratedName string `json:"generatedName,omitempty"`
+
+	// ReferringResource is the resource whose content named this resource for a resourceReference.
+	ReferringResource string `json:"referringResource,omitempty"`
+	// Matches are the JSONPath or expression results that named this resource for a resourceReference.
+	Matches []ReferenceMatch `json:"matches,omitempty"`
+
+	// Owner is the resource in the ownerReferences of this resource for ownedResources.
+	Owner string `json:"owner,omitempty"`
+}
+
+type ReferenceMatch struct {
+	// FieldPath is the path to the JSONPath or expression.
+	FieldPath string `json:"fieldPath"`
+	Query     string `json:"query"`
+	Value     string `json:"value"`
+}
+
+// resolutionReport accumulates information while resolving a ResourceList.  A nil report records nothing.
+type resolutionReport struct {
+	missing []MissingInputResource
+
+	// rules holds the field path of every rule, in order.
+	rules []string
+	// selectingRules holds the field path of every rule that selected at least one resource.
+	selectingRules sets.Set[string]
+	// selected holds every selected resource and why it was selected, keyed by Resource.ID().
+	selected map[string]*ExplainedInputResource
+}
+
+func (r *resolutionReport) rule(fieldPath *field.Path) {
+	if r == nil {
+		return
+	}
+	r.rules = append(r.rules, fieldPath.String())
+}
+
+// selectedBy records that ruleFieldPath selected the resources.
+func (r *resolutionReport) selectedBy(ruleFieldPath *field.Path, selection InputResourceSelection, resources ...*Resource) {
+	if r == nil {
+		return
+	}
+	if r.selectingRules == nil {
+		r.selectingRules = sets.New[string]()
+		r.selected = map[string]*ExplainedInputResource{}
+	}
+	if len(resources) > 0 {
+		r.selectingRules.Insert(ruleFieldPath.String())
+	}
+	for _, resource := range resources {
+		explained, ok := r.selected[resource.ID()]
+		if !ok {
+			explained = &ExplainedInputResource{
+				Resource: ExactResourceID{
+					InputResourceTypeIdentifier: InputResourceTypeIdentifier{
+						Group:    resource.ResourceType.Group,
+						Version:  resource.ResourceType.Version,
+						Resource: resource.ResourceType.Resource,
+					},
+					Namespace: resource.Content.GetNamespace(),
+					Name:      resource.Content.GetName(),
+				},
+			}
+			r.selected[resource.ID()] = explained
+		}
+		explained.SelectedBy = append(explained.SelectedBy, selection)
+	}
+}
+
+func (r *resolutionReport) missingExactResource(fieldPath *field.Path, resource ExactResourceID, referringResource string) {
+	if r == nil {
+		return
+	}
+	r.missing = append(r.missing, MissingInputResource{
+		FieldPath:         fieldPath.String(),
+		ExactResource:     &resource,
+		ReferringResource: referringResource,
+	})
+}
+
+func (r *resolutionReport) missingGeneratedNameResource(fieldPath *field.Path, resource GeneratedResourceID) {
+	if r == nil {
+		return
+	}
+	r.missing = append(r.missing, MissingInputResource{
+		FieldPath:             fieldPath.String(),
+		GeneratedNameResource: &resource,
+	})
+}
+
+func (r *resolutionReport) missingLabelSelectedResource(fieldPath *field.Path, resource LabelSelectedResource) {
+	if r == nil {
+		return
+	}
+	r.missing = append(r.missing, MissingInputResource{
+		FieldPath:             fieldPath.String(),
+		LabelSelectedResource: &resource,
+	})
+}
+
+func (r *resolutionReport) missingOwnedResource(fieldPath *field.Path, resource OwnedResource) {
+	if r == nil {
+		return
+	}
+	r.missing = append(r.missing, MissingInputResource{
+		FieldPath:     fieldPath.String(),
+		OwnedResource: &resource,
+	})
+}
+
+func (r *resolutionReport) missingInputResources() *MissingInputResources {
+	ret := &MissingInputResources{
+		MissingResources: []MissingInputResource{},
+	}
+	if r == nil {
+		return ret
+	}
+	ret.MissingResources = append(ret.MissingResources, r.missing...)
+	return ret
+}
+
+func (r *resolutionReport) explainedInputResources() *ExplainedInputResources {
+	ret := &ExplainedInputResources{
+		Resources:             []ExplainedInputResource{},
+		RulesSelectingNothing: []string{},
+	}
+	if r == nil {
+		return ret
+	}
+	for _, id := range sets.List(sets.KeySet(r.selected)) {
+		ret.Resources = append(ret.Resources, *r.selected[id])
+	}
+	for _, rule := range r.rules {
+		if !r.selectingRules.Has(rule) {
+			ret.RulesSelectingNothing = append(ret.RulesSelectingNothing, rule)
+		}
+	}
+	return ret
+}
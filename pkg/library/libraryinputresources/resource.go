@@ -13,6 +13,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/multi-operator-manager/pkg/library/librarymustgather"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -34,8 +35,8 @@ func (r Resource) ID() string {
 	return fmt.Sprintf("%s/%s/%s/%s", r.ResourceType.Group, r.ResourceType.Resource, namespace, name)
 }
 
-func discoverResourcesFromMustGather(mustGatherDir string) (map[schema.GroupVersionKind][]schema.GroupVersionResource, error) {
-	discoveryClient, err := NewDiscoveryClientFromMustGather(mustGatherDir)
+func discoverResourcesFromMustGather(mustGatherFS fs.FS) (map[schema.GroupVersionKind][]schema.GroupVersionResource, error) {
+	discoveryClient, err := NewDiscoveryClientFromFS(mustGatherFS)
 	if err != nil {
 		return nil, fmt.Errorf("failed creating discovery client: %w", err)
 	}
@@ -67,17 +68,26 @@ func discoverResourcesFromMustGather(mustGatherDir string) (map[schema.GroupVers
 	return gvkToResources, nil
 }
 
-func LenientResourcesFromDirRecursive(location string) ([]*Resource, error) {
-	gvkToResources, err := discoverResourcesFromMustGather(location)
+// LenientResourcesFromDirRecursive reads every resource in the must-gather at location, which may also be an archive.
+// See librarymustgather.NewFS.  Resources whose kind cannot be mapped to a single resource are skipped and a warning
+// is returned for each of them, so that one aliased kind does not prevent reading the rest of the must-gather.
+func LenientResourcesFromDirRecursive(location string) ([]*Resource, []string, error) {
+	mustGatherFS, err := librarymustgather.NewFS(location)
 	if err != nil {
-		return nil, fmt.Errorf("failed to discover resources from must-gather: %w", err)
+		return nil, nil, err
+	}
+	gvkToResources, err := discoverResourcesFromMustGather(mustGatherFS)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to discover resources from must-gather: %w", err)
 	}
 
 	currResourceList := []*Resource{}
+	warnings := []string{}
 	errs := []error{}
-	err = filepath.WalkDir(location, func(currLocation string, currFile fs.DirEntry, err error) error {
+	err = fs.WalkDir(mustGatherFS, ".", func(currLocation string, currFile fs.DirEntry, err error) error {
 		if err != nil {
 			errs = append(errs, err)
+			return nil
 		}
 
 		if currFile.IsDir() {
@@ -86,11 +96,16 @@ func LenientResourcesFromDirRecursive(location string) ([]*Resource, error) {
 		if !strings.HasSuffix(currFile.Name(), ".yaml") && !strings.HasSuffix(currFile.Name(), ".json") {
 			return nil
 		}
-		currResource, err := ResourcesFromFile(gvkToResources, currLocation, location)
+		content, err := fs.ReadFile(mustGatherFS, currLocation)
+		if err != nil {
+			return fmt.Errorf("unable to read %q: %w", currLocation, err)
+		}
+		currResource, currWarnings, err := resourcesFromContent(gvkToResources, content, currLocation)
 		if err != nil {
 			return fmt.Errorf("error deserializing %q: %w", currLocation, err)
 		}
 		currResourceList = append(currResourceList, currResource...)
+		warnings = append(warnings, currWarnings...)
 
 		return nil
 	})
@@ -98,80 +113,141 @@ func LenientResourcesFromDirRecursive(location string) ([]*Resource, error) {
 		errs = append(errs, err)
 	}
 
-	return currResourceList, errors.Join(errs...)
+	return currResourceList, warnings, errors.Join(errs...)
+}
+
+// ambiguousResourceError is returned by findGVR when discovery maps the kind to more than one resource and the
+// filename does not pick one of them.
+type ambiguousResourceError struct {
+	gvk       schema.GroupVersionKind
+	resources []schema.GroupVersionResource
+}
+
+func (e *ambiguousResourceError) Error() string {
+	resourceNames := []string{}
+	for _, curr := range e.resources {
+		resourceNames = append(resourceNames, resourceTypeString(curr))
+	}
+	return fmt.Sprintf("multiple resources found for Group: %q, Version: %q, Kind: %q: %v", e.gvk.Group, e.gvk.Version, e.gvk.Kind, strings.Join(resourceNames, ", "))
 }
 
-func findGVR(gvkToResources map[schema.GroupVersionKind][]schema.GroupVersionResource, gvk schema.GroupVersionKind) (*schema.GroupVersionResource, error) {
+// findGVR maps gvk to a resource.  When discovery has more than one resource for the kind, like an aggregated API that
+// aliases a kind, the must-gather filename is used to break the tie.
+func findGVR(gvkToResources map[schema.GroupVersionKind][]schema.GroupVersionResource, gvk schema.GroupVersionKind, filename string) (*schema.GroupVersionResource, error) {
 	resources := gvkToResources[gvk]
 	switch len(resources) {
 	case 1:
 		return &resources[0], nil
 	case 0:
 		return nil, fmt.Errorf("no resources found for Group: %q, Version: %q, Kind: %q", gvk.Group, gvk.Version, gvk.Kind)
-	default:
-		return nil, fmt.Errorf("multiple resources found for Group: %q, Version: %q, Kind: %q", gvk.Group, gvk.Version, gvk.Kind)
 	}
+
+	if groupResource, ok := groupResourceFromMustGatherPath(filename); ok {
+		for i := range resources {
+			if resources[i].GroupResource() == groupResource {
+				return &resources[i], nil
+			}
+		}
+	}
+	return nil, &ambiguousResourceError{gvk: gvk, resources: resources}
+}
+
+// groupResourceFromMustGatherPath returns the group and resource named by a must-gather path like
+// namespaces/<namespace>/<group>/<resource>.yaml or cluster-scoped-resources/<group>/<resource>/<name>.yaml.
+// The core group is written as "core".
+func groupResourceFromMustGatherPath(filename string) (schema.GroupResource, bool) {
+	segments := strings.Split(path.Clean(filepath.ToSlash(filename)), "/")
+	for i, segment := range segments {
+		var groupIndex int
+		switch segment {
+		case "namespaces":
+			groupIndex = i + 2
+		case "cluster-scoped-resources":
+			groupIndex = i + 1
+		default:
+			continue
+		}
+		if groupIndex+1 >= len(segments) {
+			return schema.GroupResource{}, false
+		}
+		group := segments[groupIndex]
+		if group == "core" {
+			group = ""
+		}
+		resource := strings.TrimSuffix(strings.TrimSuffix(segments[groupIndex+1], ".yaml"), ".json")
+		return schema.GroupResource{Group: group, Resource: resource}, true
+	}
+	return schema.GroupResource{}, false
 }
 
-func ResourcesFromFile(gvkToResources map[schema.GroupVersionKind][]schema.GroupVersionResource, location, fileTrimPrefix string) ([]*Resource, error) {
+// ResourcesFromFile reads the resource or list of resources at location.  Resources whose kind cannot be mapped to a
+// single resource are skipped and a warning is returned for each of them.
+func ResourcesFromFile(gvkToResources map[schema.GroupVersionKind][]schema.GroupVersionResource, location, fileTrimPrefix string) ([]*Resource, []string, error) {
 	content, err := os.ReadFile(location)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read %q: %w", location, err)
+		return nil, nil, fmt.Errorf("unable to read %q: %w", location, err)
 	}
 
+	retFilename := strings.TrimPrefix(location, fileTrimPrefix)
+	retFilename = strings.TrimPrefix(retFilename, "/")
+	return resourcesFromContent(gvkToResources, content, retFilename)
+}
+
+// resourcesFromContent decodes the resource or list of resources in content, which was read from filename.
+// A resource whose kind maps to more than one resource, even after considering filename, is skipped with a warning so
+// that one aliased kind does not prevent reading the rest of a must-gather.
+func resourcesFromContent(gvkToResources map[schema.GroupVersionKind][]schema.GroupVersionResource, content []byte, filename string) ([]*Resource, []string, error) {
 	ret, _, jsonErr := unstructured.UnstructuredJSONScheme.Decode(content, nil, &unstructured.Unstructured{})
 	if jsonErr != nil {
 		// try to see if it's yaml
 		jsonString, err := yaml.YAMLToJSON(content)
 		if err != nil {
-			return nil, fmt.Errorf("unable to decode %q as json: %w", location, jsonErr)
+			return nil, nil, fmt.Errorf("unable to decode %q as json: %w", filename, jsonErr)
 		}
 		ret, _, err = unstructured.UnstructuredJSONScheme.Decode(jsonString, nil, &unstructured.Unstructured{})
 		if err != nil {
-			return nil, fmt.Errorf("unable to decode %q as yaml: %w", location, err)
+			return nil, nil, fmt.Errorf("unable to decode %q as yaml: %w", filename, err)
 		}
 	}
 
-	retFilename := strings.TrimPrefix(location, fileTrimPrefix)
-	retFilename = strings.TrimPrefix(retFilename, "/")
 	retContent := ret.(*unstructured.Unstructured)
 
 	resource := &Resource{
-		Filename: retFilename,
+		Filename: filename,
 		Content:  retContent,
 	}
 
-	// Short-circuit if the file contains a single resource
-	if !resource.Content.IsList() {
-		gvk := retContent.GroupVersionKind()
-		gvr, err := findGVR(gvkToResources, gvk)
+	items := []unstructured.Unstructured{*retContent}
+	if resource.Content.IsList() {
+		// Unpack if the file contains a list of resources
+		list, err := resource.Content.ToList()
 		if err != nil {
-			return nil, fmt.Errorf("failed to find gvr: %w", err)
+			return nil, nil, fmt.Errorf("unable to convert resource content to list: %w", err)
 		}
-		resource.ResourceType = *gvr
-		return []*Resource{resource}, nil
-	}
-
-	// Unpack if the file contains a list of resources
-	list, err := resource.Content.ToList()
-	if err != nil {
-		return nil, fmt.Errorf("unable to convert resource content to list: %w", err)
+		items = list.Items
 	}
 
-	resources := make([]*Resource, 0, len(list.Items))
-	for _, item := range list.Items {
-		gvr, err := findGVR(gvkToResources, item.GroupVersionKind())
+	resources := make([]*Resource, 0, len(items))
+	warnings := []string{}
+	for i := range items {
+		item := &items[i]
+		gvr, err := findGVR(gvkToResources, item.GroupVersionKind(), filename)
+		var ambiguousErr *ambiguousResourceError
+		if errors.As(err, &ambiguousErr) {
+			warnings = append(warnings, fmt.Sprintf("skipping %s/%s[%s] in %q: %v, and the path does not name one of them", item.GetKind(), item.GetName(), item.GetNamespace(), filename, ambiguousErr))
+			continue
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to find gvr: %w", err)
+			return nil, nil, fmt.Errorf("failed to find gvr: %w", err)
 		}
 		resources = append(resources, &Resource{
 			Filename:     resource.Filename,
-			Content:      &item,
+			Content:      item,
 			ResourceType: *gvr,
 		})
 	}
 
-	return resources, nil
+	return resources, warnings, nil
 }
 
 func IdentifyResource(in *Resource) string {
@@ -0,0 +1,125 @@
+package libraryinputresources
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestResourcesFromContentAmbiguousKind(t *testing.T) {
+	gvkToResources := map[schema.GroupVersionKind][]schema.GroupVersionResource{
+		{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics"}: {
+			{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"},
+			{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "podmetrics"},
+		},
+		{Group: "", Version: "v1", Kind: "ConfigMap"}: {
+			{Group: "", Version: "v1", Resource: "configmaps"},
+		},
+	}
+	content := []byte(`apiVersion: v1
+kind: List
+items:
+- apiVersion: metrics.k8s.io/v1beta1
+  kind: PodMetrics
+  metadata:
+    name: first
+    namespace: openshift-etcd
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: second
+    namespace: openshift-etcd
+`)
+
+	tests := []struct {
+		name              string
+		filename          string
+		expectedResources []schema.GroupVersionResource
+		expectedWarning   string
+	}{
+		{
+			name:     "namespaced list path",
+			filename: "namespaces/openshift-etcd/metrics.k8s.io/pods.yaml",
+			expectedResources: []schema.GroupVersionResource{
+				{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"},
+				{Group: "", Version: "v1", Resource: "configmaps"},
+			},
+		},
+		{
+			name:     "individual resource path",
+			filename: "must-gather/namespaces/openshift-etcd/metrics.k8s.io/podmetrics/first.yaml",
+			expectedResources: []schema.GroupVersionResource{
+				{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "podmetrics"},
+				{Group: "", Version: "v1", Resource: "configmaps"},
+			},
+		},
+		{
+			name:     "path without a match",
+			filename: "namespaces/openshift-etcd/core/configmaps.yaml",
+			expectedResources: []schema.GroupVersionResource{
+				{Group: "", Version: "v1", Resource: "configmaps"},
+			},
+			expectedWarning: `skipping PodMetrics/first[openshift-etcd] in "namespaces/openshift-etcd/core/configmaps.yaml": multiple resources found for Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics": pods.v1beta1.metrics.k8s.io, podmetrics.v1beta1.metrics.k8s.io, and the path does not name one of them`,
+		},
+		{
+			name:     "not a must-gather path",
+			filename: "resources.yaml",
+			expectedResources: []schema.GroupVersionResource{
+				{Group: "", Version: "v1", Resource: "configmaps"},
+			},
+			expectedWarning: `skipping PodMetrics/first[openshift-etcd] in "resources.yaml"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, warnings, err := resourcesFromContent(gvkToResources, content, tt.filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actualResources := []schema.GroupVersionResource{}
+			for _, curr := range actual {
+				actualResources = append(actualResources, curr.ResourceType)
+			}
+			if !reflect.DeepEqual(tt.expectedResources, actualResources) {
+				t.Errorf("expected %v, got %v", tt.expectedResources, actualResources)
+			}
+			switch {
+			case len(tt.expectedWarning) == 0 && len(warnings) > 0:
+				t.Errorf("unexpected warnings: %v", warnings)
+			case len(tt.expectedWarning) > 0 && (len(warnings) != 1 || !strings.HasPrefix(warnings[0], tt.expectedWarning)):
+				t.Errorf("expected warning %q, got %v", tt.expectedWarning, warnings)
+			}
+		})
+	}
+}
+
+func TestResourcesFromFileAmbiguousKind(t *testing.T) {
+	gvkToResources := map[schema.GroupVersionKind][]schema.GroupVersionResource{
+		{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics"}: {
+			{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"},
+			{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "podmetrics"},
+		},
+	}
+	location := filepath.Join(t.TempDir(), "resources.yaml")
+	content := []byte(`apiVersion: metrics.k8s.io/v1beta1
+kind: PodMetrics
+metadata:
+  name: first
+  namespace: openshift-etcd
+`)
+	if err := os.WriteFile(location, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, warnings, err := ResourcesFromFile(gvkToResources, location, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 0 || len(warnings) != 1 || !strings.HasPrefix(warnings[0], "skipping PodMetrics/first[openshift-etcd]") {
+		t.Errorf("expected one warning and no resources, got %v and %v", actual, warnings)
+	}
+}
@@ -11,7 +11,8 @@ type InputResources struct {
 	// It is the responsibility of the MOM to determine where the inputs come from.
 	ApplyConfigurationResources ResourceList `json:"applyConfigurationResources,omitempty"`
 
-	// operandResources is the list of resources that are important for determining check-health
+	// operandResources is the list of resources that are important for determining check-health.
+	// When pruning a must-gather, these are written to Configuration/, Management/, and UserWorkload/ subdirectories.
 	OperandResources OperandResourceList `json:"operandResources,omitempty"`
 }
 
@@ -25,15 +26,44 @@ type ResourceList struct {
 	// use resourceReferences when one resource (apiserver.config.openshift.io/cluster) refers to another resource
 	// like a secret (.spec.servingCerts.namedCertificates[*].servingCertificates.name).
 	ResourceReferences []ResourceReference `json:"resourceReferences,omitempty"`
+
+	// use ownedResources to select everything owned by a resource, like the replicasets and pods of a deployment.
+	OwnedResources []OwnedResource `json:"ownedResources,omitempty"`
+}
+
+// OwnedResource selects the resources whose ownerReferences point at the owner.
+type OwnedResource struct {
+	// owner is the resource whose dependents are selected.  The owner is selected as well.
+	Owner ExactResourceID `json:"owner"`
+
+	// ownedResourceTypes are the types of the dependents, like replicasets and pods for a deployment.
+	OwnedResourceTypes []InputResourceTypeIdentifier `json:"ownedResourceTypes"`
+
+	// namespace is where dependents are listed.  It defaults to the namespace of the owner and is required for
+	// cluster-scoped owners.  Namespaced dependents cannot be owned across namespaces.
+	Namespace string `json:"namespace,omitempty"`
+
+	// recursive also selects the dependents of selected dependents until no new resources are found.
+	// With a deployment owner and ownedResourceTypes of replicasets and pods, recursive selects the pods of the
+	// deployment's replicasets.  Without it, only resources directly owned by the owner are selected.
+	Recursive bool `json:"recursive,omitempty"`
 }
 
 type LabelSelectedResource struct {
+	// name is optional and must be unique among labelSelectedResources in a resourceList.
+	// Naming a labelSelectedResource allows a resourceReference to use the selected resources as referring resources.
+	Name string `json:"name,omitempty"`
+
 	InputResourceTypeIdentifier `json:",inline"`
 
 	Namespace string `json:"namespace,omitempty"`
 
-	// validation prevents setting matchExpressions
+	// labelSelector supports both matchLabels and matchExpressions.
 	LabelSelector metav1.LabelSelector `json:"labelSelector"`
+
+	// fields is an optional list of JSONPaths to keep in every selected resource.
+	// See ExactResourceID.Fields.
+	Fields []string `json:"fields,omitempty"`
 }
 
 type OperandResourceList struct {
@@ -47,6 +77,11 @@ type ExactResourceID struct {
 
 	Namespace string `json:"namespace,omitempty"`
 	Name      string `json:"name"`
+
+	// fields is an optional list of JSONPaths to keep when pruning, like .spec.desiredUpdate or .data['config.yaml'].
+	// Only child names and [*] are supported.  Identity metadata (apiVersion, kind, name, namespace, labels) is always kept.
+	// When empty, or when another rule selects the same resource without fields, the whole resource is kept.
+	Fields []string `json:"fields,omitempty"`
 }
 
 type GeneratedResourceID struct {
@@ -57,9 +92,20 @@ type GeneratedResourceID struct {
 }
 
 type ResourceReference struct {
-	// TODO determine if we need the ability to select multiple containing resources.  I don’t think we’ll need to given the shape of our configuration.
+	// name is optional and must be unique among resourceReferences in a resourceList.
+	// Naming a resourceReference allows another resourceReference to use the referenced resources as referring resources.
+	Name string `json:"name,omitempty"`
+
+	// referringResource is the single resource that contains the reference.
+	// Exactly one of referringResource and referringResourcesFrom must be set.  An empty referringResource is treated as
+	// omitted, so it must be left empty when referringResourcesFrom is set.
 	ReferringResource ExactResourceID `json:"referringResource"`
 
+	// referringResourcesFrom uses every resource selected by another rule in the same resourceList as a referring resource.
+	// This allows chains like apiserver config -> secret -> the configmap named in an annotation on that secret.
+	// Chains are followed until no new resources are found.
+	ReferringResourcesFrom *ReferringResourcesFrom `json:"referringResourcesFrom,omitempty"`
+
 	Type ResourceReferenceType `json:"type"`
 
 	ExplicitNamespacedReference *ExplicitNamespacedReference `json:"explicitNamespacedReference,omitempty"`
@@ -67,6 +113,15 @@ type ResourceReference struct {
 	ClusterScopedReference      *ClusterScopedReference      `json:"clusterScopedReference,omitempty"`
 }
 
+// ReferringResourcesFrom names other rules in the same ResourceList. At least one name must be listed.
+// A resourceReference may list itself to follow links between resources of the same type until no new resources are found.
+type ReferringResourcesFrom struct {
+	// resourceReferences are the names of resourceReferences whose referenced resources are referring resources.
+	ResourceReferences []string `json:"resourceReferences,omitempty"`
+	// labelSelectedResources are the names of labelSelectedResources whose selected resources are referring resources.
+	LabelSelectedResources []string `json:"labelSelectedResources,omitempty"`
+}
+
 type ResourceReferenceType string
 
 const (
@@ -79,9 +134,17 @@ type ExplicitNamespacedReference struct {
 	InputResourceTypeIdentifier `json:",inline"`
 
 	// may have multiple matches
-	// TODO CEL may be more appropriate
-	NamespaceJSONPath string `json:"namespaceJSONPath"`
-	NameJSONPath      string `json:"nameJSONPath"`
+	// exactly one of namespaceJSONPath and namespaceExpression must be set.
+	NamespaceJSONPath string `json:"namespaceJSONPath,omitempty"`
+	// exactly one of nameJSONPath and nameExpression must be set.
+	NameJSONPath string `json:"nameJSONPath,omitempty"`
+
+	// namespaceExpression is a CEL expression evaluated with the referring resource bound to `object`.
+	// It must produce a string or a list of strings.
+	NamespaceExpression string `json:"namespaceExpression,omitempty"`
+	// nameExpression is a CEL expression evaluated with the referring resource bound to `object`.
+	// It must produce a string or a list of strings.
+	NameExpression string `json:"nameExpression,omitempty"`
 }
 
 type ImplicitNamespacedReference struct {
@@ -89,16 +152,24 @@ type ImplicitNamespacedReference struct {
 
 	Namespace string `json:"namespace"`
 	// may have multiple matches
-	// TODO CEL may be more appropriate
-	NameJSONPath string `json:"nameJSONPath"`
+	// exactly one of nameJSONPath and nameExpression must be set.
+	NameJSONPath string `json:"nameJSONPath,omitempty"`
+
+	// nameExpression is a CEL expression evaluated with the referring resource bound to `object`.
+	// It must produce a string or a list of strings.
+	NameExpression string `json:"nameExpression,omitempty"`
 }
 
 type ClusterScopedReference struct {
 	InputResourceTypeIdentifier `json:",inline"`
 
 	// may have multiple matches
-	// TODO CEL may be more appropriate
-	NameJSONPath string `json:"nameJSONPath"`
+	// exactly one of nameJSONPath and nameExpression must be set.
+	NameJSONPath string `json:"nameJSONPath,omitempty"`
+
+	// nameExpression is a CEL expression evaluated with the referring resource bound to `object`.
+	// It must produce a string or a list of strings.
+	NameExpression string `json:"nameExpression,omitempty"`
 }
 
 type InputResourceTypeIdentifier struct {
@@ -1,6 +1,13 @@
 package libraryinputresources
 
-import "k8s.io/apimachinery/pkg/util/validation/field"
+import (
+	"fmt"
+
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryoutputresources"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
 
 func validateInputResources(obj *InputResources) []error {
 	errs := []error{}
@@ -27,11 +34,35 @@ func validateResourceList(path *field.Path, obj ResourceList) []error {
 	for i, curr := range obj.ExactResources {
 		errs = append(errs, validateExactResourceID(path.Child("exactResources").Index(i), curr)...)
 	}
+	for i, curr := range obj.GeneratedNameResources {
+		errs = append(errs, validateGeneratedResourceID(path.Child("generatedNameResources").Index(i), curr)...)
+	}
+	labelSelectedResourceNames := sets.New[string]()
 	for i, curr := range obj.LabelSelectedResources {
 		errs = append(errs, validateLabelSelectedResources(path.Child("labelSelectedResources").Index(i), curr)...)
+		if len(curr.Name) == 0 {
+			continue
+		}
+		if labelSelectedResourceNames.Has(curr.Name) {
+			errs = append(errs, field.Duplicate(path.Child("labelSelectedResources").Index(i).Child("name"), curr.Name))
+		}
+		labelSelectedResourceNames.Insert(curr.Name)
 	}
+	resourceReferenceNames := sets.New[string]()
 	for i, curr := range obj.ResourceReferences {
-		errs = append(errs, validateResourceReference(path.Child("resourceReferences").Index(i), curr)...)
+		if len(curr.Name) == 0 {
+			continue
+		}
+		if resourceReferenceNames.Has(curr.Name) {
+			errs = append(errs, field.Duplicate(path.Child("resourceReferences").Index(i).Child("name"), curr.Name))
+		}
+		resourceReferenceNames.Insert(curr.Name)
+	}
+	for i, curr := range obj.ResourceReferences {
+		errs = append(errs, validateResourceReference(path.Child("resourceReferences").Index(i), curr, resourceReferenceNames, labelSelectedResourceNames)...)
+	}
+	for i, curr := range obj.OwnedResources {
+		errs = append(errs, validateOwnedResource(path.Child("ownedResources").Index(i), curr)...)
 	}
 
 	return errs
@@ -44,6 +75,18 @@ func validateExactResourceID(path *field.Path, obj ExactResourceID) []error {
 	if len(obj.Name) == 0 {
 		errs = append(errs, field.Required(path.Child("name"), "must be present"))
 	}
+	errs = append(errs, validateFields(path.Child("fields"), obj.Fields)...)
+
+	return errs
+}
+
+func validateGeneratedResourceID(path *field.Path, obj GeneratedResourceID) []error {
+	errs := []error{}
+
+	errs = append(errs, validateInputResourceTypeIdentifier(path, obj.InputResourceTypeIdentifier)...)
+	if len(obj.GeneratedName) == 0 {
+		errs = append(errs, field.Required(path.Child("name"), "must be present"))
+	}
 
 	return errs
 }
@@ -52,9 +95,42 @@ func validateLabelSelectedResources(path *field.Path, obj LabelSelectedResource)
 	errs := []error{}
 
 	errs = append(errs, validateInputResourceTypeIdentifier(path, obj.InputResourceTypeIdentifier)...)
-	if len(obj.LabelSelector.MatchExpressions) > 0 {
-		errs = append(errs, field.Forbidden(path.Child("matchExpressions"), "not supported"))
+	for _, curr := range metav1validation.ValidateLabelSelector(&obj.LabelSelector, metav1validation.LabelSelectorValidationOptions{}, path.Child("labelSelector")) {
+		errs = append(errs, curr)
+	}
+	errs = append(errs, validateFields(path.Child("fields"), obj.Fields)...)
+	return errs
+}
+
+func validateOwnedResource(path *field.Path, obj OwnedResource) []error {
+	errs := []error{}
+
+	errs = append(errs, validateExactResourceID(path.Child("owner"), obj.Owner)...)
+	if len(obj.OwnedResourceTypes) == 0 {
+		errs = append(errs, field.Required(path.Child("ownedResourceTypes"), "must have at least one entry"))
+	}
+	for i, curr := range obj.OwnedResourceTypes {
+		errs = append(errs, validateInputResourceTypeIdentifier(path.Child("ownedResourceTypes").Index(i), curr)...)
+	}
+	switch {
+	case len(obj.Namespace) == 0 && len(obj.Owner.Namespace) == 0:
+		errs = append(errs, field.Required(path.Child("namespace"), "must be present for cluster-scoped owners"))
+	case len(obj.Namespace) > 0 && len(obj.Owner.Namespace) > 0 && obj.Namespace != obj.Owner.Namespace:
+		errs = append(errs, field.Invalid(path.Child("namespace"), obj.Namespace, "must match the namespace of the owner, resources cannot be owned across namespaces"))
 	}
+
+	return errs
+}
+
+func validateFields(path *field.Path, fields []string) []error {
+	errs := []error{}
+
+	for i, curr := range fields {
+		if _, err := libraryoutputresources.ParseFieldPath(curr); err != nil {
+			errs = append(errs, field.Invalid(path.Index(i), curr, err.Error()))
+		}
+	}
+
 	return errs
 }
 
@@ -71,16 +147,62 @@ func validateInputResourceTypeIdentifier(path *field.Path, obj InputResourceType
 	return errs
 }
 
-func validateResourceReference(path *field.Path, obj ResourceReference) []error {
+func validateResourceReference(path *field.Path, obj ResourceReference, resourceReferenceNames, labelSelectedResourceNames sets.Set[string]) []error {
 	errs := []error{}
 
-	errs = append(errs, validateExactResourceID(path.Child("referringResource"), obj.ReferringResource)...)
+	switch {
+	case obj.ReferringResourcesFrom != nil:
+		if !isEmptyExactResourceID(obj.ReferringResource) {
+			errs = append(errs, field.Forbidden(path.Child("referringResource"), "may not be set when referringResourcesFrom is set"))
+		}
+		errs = append(errs, validateReferringResourcesFrom(path.Child("referringResourcesFrom"), obj.ReferringResourcesFrom, resourceReferenceNames, labelSelectedResourceNames)...)
+	case isEmptyExactResourceID(obj.ReferringResource):
+		errs = append(errs, field.Required(path.Child("referringResource"), "one of referringResource and referringResourcesFrom must be present"))
+	default:
+		errs = append(errs, validateExactResourceID(path.Child("referringResource"), obj.ReferringResource)...)
+	}
 
 	switch obj.Type {
 	case ImplicitNamespacedReferenceType:
+		if obj.ImplicitNamespacedReference == nil {
+			errs = append(errs, field.Required(path.Child("implicitNamespacedReference"), "must be present for type=ImplicitNamespacedReference"))
+			break
+		}
 		errs = append(errs, validateImplicitNamespaceReference(path.Child("implicitNamespacedReference"), obj.ImplicitNamespacedReference)...)
+	case ExplicitNamespacedReferenceType:
+		if obj.ExplicitNamespacedReference == nil {
+			errs = append(errs, field.Required(path.Child("explicitNamespacedReference"), "must be present for type=ExplicitNamespacedReference"))
+			break
+		}
+		errs = append(errs, validateExplicitNamespaceReference(path.Child("explicitNamespacedReference"), obj.ExplicitNamespacedReference)...)
+	case ClusterScopedReferenceType:
+		if obj.ClusterScopedReference == nil {
+			errs = append(errs, field.Required(path.Child("clusterScopedReference"), "must be present for type=ClusterScopedReference"))
+			break
+		}
+		errs = append(errs, validateClusterScopedReference(path.Child("clusterScopedReference"), obj.ClusterScopedReference)...)
 	default:
-		errs = append(errs, field.NotSupported(path.Child("type"), obj.Type, []ResourceReferenceType{ImplicitNamespacedReferenceType}))
+		errs = append(errs, field.NotSupported(path.Child("type"), obj.Type, []ResourceReferenceType{ImplicitNamespacedReferenceType, ExplicitNamespacedReferenceType, ClusterScopedReferenceType}))
+	}
+
+	return errs
+}
+
+func validateReferringResourcesFrom(path *field.Path, obj *ReferringResourcesFrom, resourceReferenceNames, labelSelectedResourceNames sets.Set[string]) []error {
+	errs := []error{}
+
+	if len(obj.ResourceReferences) == 0 && len(obj.LabelSelectedResources) == 0 {
+		errs = append(errs, field.Required(path, "at least one of resourceReferences and labelSelectedResources must be set"))
+	}
+	for i, name := range obj.ResourceReferences {
+		if !resourceReferenceNames.Has(name) {
+			errs = append(errs, field.NotFound(path.Child("resourceReferences").Index(i), name))
+		}
+	}
+	for i, name := range obj.LabelSelectedResources {
+		if !labelSelectedResourceNames.Has(name) {
+			errs = append(errs, field.NotFound(path.Child("labelSelectedResources").Index(i), name))
+		}
 	}
 
 	return errs
@@ -94,10 +216,57 @@ func validateImplicitNamespaceReference(path *field.Path, obj *ImplicitNamespace
 		errs = append(errs, field.Required(path.Child("namespace"), "must be present"))
 	}
 
-	_, err := builder.NewEvaluable(obj.NameJSONPath)
+	errs = append(errs, validateReferenceValueSource(path, "name", obj.NameJSONPath, obj.NameExpression)...)
+
+	return errs
+}
+
+func validateExplicitNamespaceReference(path *field.Path, obj *ExplicitNamespacedReference) []error {
+	errs := []error{}
+
+	errs = append(errs, validateInputResourceTypeIdentifier(path, obj.InputResourceTypeIdentifier)...)
+
+	errs = append(errs, validateReferenceValueSource(path, "namespace", obj.NamespaceJSONPath, obj.NamespaceExpression)...)
+	errs = append(errs, validateReferenceValueSource(path, "name", obj.NameJSONPath, obj.NameExpression)...)
+
+	return errs
+}
+
+func validateClusterScopedReference(path *field.Path, obj *ClusterScopedReference) []error {
+	errs := []error{}
+
+	errs = append(errs, validateInputResourceTypeIdentifier(path, obj.InputResourceTypeIdentifier)...)
+
+	errs = append(errs, validateReferenceValueSource(path, "name", obj.NameJSONPath, obj.NameExpression)...)
+
+	return errs
+}
+
+// validateReferenceValueSource ensures that exactly one of <prefix>JSONPath and <prefix>Expression is used and that it compiles.
+func validateReferenceValueSource(path *field.Path, prefix, jsonPath, expression string) []error {
+	errs := []error{}
+
+	jsonPathField := path.Child(prefix + "JSONPath")
+	expressionField := path.Child(prefix + "Expression")
+	if len(expression) > 0 {
+		if len(jsonPath) > 0 {
+			errs = append(errs, field.Forbidden(jsonPathField, fmt.Sprintf("may not be set when %v is set", expressionField)))
+		}
+		if _, err := compileReferenceExpression(expression); err != nil {
+			errs = append(errs, field.Invalid(expressionField, expression, err.Error()))
+		}
+		return errs
+	}
+
+	_, err := builder.NewEvaluable(jsonPath)
 	if err != nil {
-		errs = append(errs, field.Invalid(path.Child("nameJSONPath"), obj.NameJSONPath, err.Error()))
+		errs = append(errs, field.Invalid(jsonPathField, jsonPath, err.Error()))
 	}
 
 	return errs
 }
+
+// isEmptyExactResourceID is true for a referringResource that was omitted.
+func isEmptyExactResourceID(obj ExactResourceID) bool {
+	return obj.InputResourceTypeIdentifier == InputResourceTypeIdentifier{} && len(obj.Namespace) == 0 && len(obj.Name) == 0 && len(obj.Fields) == 0
+}
@@ -0,0 +1,152 @@
+package libraryinputresources
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryoutputresources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/discovery"
+)
+
+// NewDefaultDiscoveryClient returns a discovery client backed by the discovery information embedded in manifestclient.
+// This is the same discovery used for a must-gather that does not include its own.
+func NewDefaultDiscoveryClient() (discovery.AggregatedDiscoveryInterface, error) {
+	return libraryoutputresources.NewDefaultDiscoveryClient()
+}
+
+// namespaceScope describes what a rule requires of the namespacing of the resource it refers to.
+type namespaceScope int
+
+const (
+	// namespaceScopeFromNamespace requires a namespaced resource when namespace is set and a cluster-scoped resource otherwise.
+	namespaceScopeFromNamespace namespaceScope = iota
+	// namespaceScopeAny allows an empty namespace for a namespaced resource, for instance a selection across all namespaces.
+	namespaceScopeAny
+	namespaceScopeNamespaced
+	namespaceScopeClusterScoped
+)
+
+type resourceTypeUse struct {
+	path       *field.Path
+	identifier InputResourceTypeIdentifier
+	namespace  string
+	scope      namespaceScope
+}
+
+// ValidateInputResourcesAgainstDiscovery checks that every resource is served and that namespaces are only used with
+// namespaced resources according to discoveryClient.  A resource that is missing from a group/version that
+// discoveryClient serves is an error, this catches typos like configmap instead of configmaps.  A group/version that
+// discoveryClient does not know is only a warning, because operators may read their own CRDs.
+//
+// The documented rule that every group,resource uses the same version is deliberately relaxed to a warning.
+// Mixed versions are written to version-qualified files by unstructuredToMustGatherFormat, so pruning them works,
+// but usually the newer version is intended.
+func ValidateInputResourcesAgainstDiscovery(obj *InputResources, discoveryClient discovery.AggregatedDiscoveryInterface) ([]string, []error) {
+	_, gvToAPIResourceList, _, err := discoveryClient.GroupsAndMaybeResources()
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to get api resource list with GroupsAndMaybeResources: %w", err)}
+	}
+	servedResources := map[schema.GroupVersionResource]metav1.APIResource{}
+	servedGroupVersions := sets.New[schema.GroupVersion]()
+	for gv, apiResourceList := range gvToAPIResourceList {
+		servedGroupVersions.Insert(gv)
+		for _, apiResource := range apiResourceList.APIResources {
+			if strings.Contains(apiResource.Name, "/") {
+				// Skip subresources
+				continue
+			}
+			servedResources[gv.WithResource(apiResource.Name)] = apiResource
+		}
+	}
+
+	uses := []resourceTypeUse{}
+	for _, currList := range resourceListsForInputResources(obj) {
+		uses = append(uses, resourceTypeUsesForResourceList(currList.fieldPath, currList.resourceList)...)
+	}
+
+	warnings := []string{}
+	errs := []error{}
+	versionsByGroupResource := map[schema.GroupResource]resourceTypeUse{}
+	for _, use := range uses {
+		gvr := schema.GroupVersionResource{Group: use.identifier.Group, Version: use.identifier.Version, Resource: use.identifier.Resource}
+		if len(gvr.Version) == 0 || len(gvr.Resource) == 0 {
+			// already reported by validateInputResources
+			continue
+		}
+
+		if existing, ok := versionsByGroupResource[gvr.GroupResource()]; !ok {
+			versionsByGroupResource[gvr.GroupResource()] = use
+		} else if existing.identifier.Version != gvr.Version {
+			warnings = append(warnings, fmt.Sprintf("%v: %v differs from %v used by %v", use.path.Child("version"), gvr.Version, existing.identifier.Version, existing.path))
+		}
+
+		apiResource, ok := servedResources[gvr]
+		switch {
+		case !ok && servedGroupVersions.Has(gvr.GroupVersion()):
+			errs = append(errs, field.NotFound(use.path.Child("resource"), resourceTypeString(gvr)))
+			continue
+		case !ok:
+			warnings = append(warnings, fmt.Sprintf("%v: %s is not served", use.path.Child("resource"), resourceTypeString(gvr)))
+			continue
+		}
+
+		switch {
+		case use.scope == namespaceScopeNamespaced && !apiResource.Namespaced:
+			errs = append(errs, field.Invalid(use.path.Child("resource"), gvr.Resource, "must be a namespaced resource"))
+		case use.scope == namespaceScopeClusterScoped && apiResource.Namespaced:
+			errs = append(errs, field.Invalid(use.path.Child("resource"), gvr.Resource, "must be a cluster-scoped resource"))
+		case use.scope == namespaceScopeFromNamespace && apiResource.Namespaced && len(use.namespace) == 0:
+			errs = append(errs, field.Required(use.path.Child("namespace"), fmt.Sprintf("%s is namespaced", resourceTypeString(gvr))))
+		case (use.scope == namespaceScopeFromNamespace || use.scope == namespaceScopeAny) && !apiResource.Namespaced && len(use.namespace) > 0:
+			errs = append(errs, field.Forbidden(use.path.Child("namespace"), fmt.Sprintf("%s is cluster-scoped", resourceTypeString(gvr))))
+		}
+	}
+
+	return warnings, errs
+}
+
+func resourceTypeString(gvr schema.GroupVersionResource) string {
+	if len(gvr.Group) == 0 {
+		return fmt.Sprintf("%s.%s", gvr.Resource, gvr.Version)
+	}
+	return fmt.Sprintf("%s.%s.%s", gvr.Resource, gvr.Version, gvr.Group)
+}
+
+func resourceTypeUsesForResourceList(path *field.Path, obj ResourceList) []resourceTypeUse {
+	uses := []resourceTypeUse{}
+	for i, curr := range obj.ExactResources {
+		uses = append(uses, resourceTypeUse{path: path.Child("exactResources").Index(i), identifier: curr.InputResourceTypeIdentifier, namespace: curr.Namespace})
+	}
+	for i, curr := range obj.GeneratedNameResources {
+		uses = append(uses, resourceTypeUse{path: path.Child("generatedNameResources").Index(i), identifier: curr.InputResourceTypeIdentifier, namespace: curr.Namespace})
+	}
+	for i, curr := range obj.LabelSelectedResources {
+		uses = append(uses, resourceTypeUse{path: path.Child("labelSelectedResources").Index(i), identifier: curr.InputResourceTypeIdentifier, namespace: curr.Namespace, scope: namespaceScopeAny})
+	}
+	for i, curr := range obj.ResourceReferences {
+		currPath := path.Child("resourceReferences").Index(i)
+		if !isEmptyExactResourceID(curr.ReferringResource) {
+			uses = append(uses, resourceTypeUse{path: currPath.Child("referringResource"), identifier: curr.ReferringResource.InputResourceTypeIdentifier, namespace: curr.ReferringResource.Namespace})
+		}
+		switch {
+		case curr.ImplicitNamespacedReference != nil:
+			uses = append(uses, resourceTypeUse{path: currPath.Child("implicitNamespacedReference"), identifier: curr.ImplicitNamespacedReference.InputResourceTypeIdentifier, scope: namespaceScopeNamespaced})
+		case curr.ExplicitNamespacedReference != nil:
+			uses = append(uses, resourceTypeUse{path: currPath.Child("explicitNamespacedReference"), identifier: curr.ExplicitNamespacedReference.InputResourceTypeIdentifier, scope: namespaceScopeNamespaced})
+		case curr.ClusterScopedReference != nil:
+			uses = append(uses, resourceTypeUse{path: currPath.Child("clusterScopedReference"), identifier: curr.ClusterScopedReference.InputResourceTypeIdentifier, scope: namespaceScopeClusterScoped})
+		}
+	}
+	for i, curr := range obj.OwnedResources {
+		currPath := path.Child("ownedResources").Index(i)
+		uses = append(uses, resourceTypeUse{path: currPath.Child("owner"), identifier: curr.Owner.InputResourceTypeIdentifier, namespace: curr.Owner.Namespace})
+		for j, ownedResourceType := range curr.OwnedResourceTypes {
+			uses = append(uses, resourceTypeUse{path: currPath.Child("ownedResourceTypes").Index(j), identifier: ownedResourceType, scope: namespaceScopeNamespaced})
+		}
+	}
+	return uses
+}
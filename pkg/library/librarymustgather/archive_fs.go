@@ -0,0 +1,160 @@
+package librarymustgather
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// archiveFS is a read-only filesystem holding the regular files of an archive.  Directories are synthesized from the
+// files they contain.  Every file is held in memory, so it needs as much memory as the uncompressed archive.
+// Call sortChildren once every file is added.
+type archiveFS struct {
+	files map[string]*archiveFile
+	// children holds the names of the entries in every directory.
+	children map[string][]string
+}
+
+type archiveFile struct {
+	name    string
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+var (
+	_ fs.FS        = &archiveFS{}
+	_ fs.ReadDirFS = &archiveFS{}
+)
+
+func newArchiveFS() *archiveFS {
+	return &archiveFS{
+		files:    map[string]*archiveFile{},
+		children: map[string][]string{".": nil},
+	}
+}
+
+// add stores a file at name, which must be a valid and clean path.
+func (a *archiveFS) add(name string, data []byte, mode fs.FileMode, modTime time.Time) {
+	if _, exists := a.files[name]; !exists {
+		a.addChild(name)
+	}
+	a.files[name] = &archiveFile{name: path.Base(name), data: data, mode: mode.Perm(), modTime: modTime}
+}
+
+func (a *archiveFS) addChild(name string) {
+	dir := path.Dir(name)
+	if _, exists := a.children[dir]; !exists {
+		a.addChild(dir)
+	}
+	a.children[dir] = append(a.children[dir], path.Base(name))
+}
+
+// sortChildren sorts the entries of every directory, so ReadDir returns them in order like os.ReadDir.
+func (a *archiveFS) sortChildren() {
+	for _, children := range a.children {
+		slices.Sort(children)
+	}
+}
+
+func (a *archiveFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if file, ok := a.files[name]; ok {
+		return &openArchiveFile{archiveFile: file, reader: bytes.NewReader(file.data)}, nil
+	}
+	if _, ok := a.children[name]; ok {
+		entries, err := a.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &openArchiveDir{info: dirInfo{name: path.Base(name)}, entries: entries}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (a *archiveFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if _, ok := a.files[name]; ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	children, ok := a.children[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, child := range children {
+		childPath := path.Join(name, child)
+		if file, ok := a.files[childPath]; ok {
+			entries = append(entries, fs.FileInfoToDirEntry(file))
+			continue
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(dirInfo{name: child}))
+	}
+	return entries, nil
+}
+
+func (f *archiveFile) Name() string       { return f.name }
+func (f *archiveFile) Size() int64        { return int64(len(f.data)) }
+func (f *archiveFile) Mode() fs.FileMode  { return f.mode }
+func (f *archiveFile) ModTime() time.Time { return f.modTime }
+func (f *archiveFile) IsDir() bool        { return false }
+func (f *archiveFile) Sys() any           { return nil }
+
+type openArchiveFile struct {
+	*archiveFile
+	reader *bytes.Reader
+}
+
+func (f *openArchiveFile) Stat() (fs.FileInfo, error) { return f.archiveFile, nil }
+func (f *openArchiveFile) Read(b []byte) (int, error) { return f.reader.Read(b) }
+func (f *openArchiveFile) Close() error               { return nil }
+
+type dirInfo struct {
+	name string
+}
+
+func (d dirInfo) Name() string       { return d.name }
+func (d dirInfo) Size() int64        { return 0 }
+func (d dirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0555 }
+func (d dirInfo) ModTime() time.Time { return time.Time{} }
+func (d dirInfo) IsDir() bool        { return true }
+func (d dirInfo) Sys() any           { return nil }
+
+type openArchiveDir struct {
+	info    dirInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *openArchiveDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *openArchiveDir) Close() error               { return nil }
+func (d *openArchiveDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *openArchiveDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	count = min(count, len(remaining))
+	d.offset += count
+	return remaining[:count], nil
+}
+
+// cleanArchivePath returns the path of an archive entry within the archiveFS.
+func cleanArchivePath(name string) string {
+	return path.Clean(strings.TrimPrefix(name, "/"))
+}
@@ -0,0 +1,146 @@
+package librarymustgather
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// maxRootDepth is how far below the top of a directory or archive we look for the must-gather content.
+// `oc adm must-gather` produces must-gather.local.<id>/<image-digest-directory>/ which is two levels deep.
+const maxRootDepth = 3
+
+// NewFS returns a filesystem rooted at the content of the must-gather at location.
+// location can be a directory or a .tar, .tar.gz, or .tgz archive.  Archives are read into memory, which takes as much
+// memory as the uncompressed archive, so build the FS once and share it, for instance with
+// libraryinputresources.NewDynamicClientFromFS, instead of calling NewFS again.  Extract very large archives instead.
+// If the content is nested, like the image-digest directory created by `oc adm must-gather`, the nested directory
+// is used as the root.
+func NewFS(location string) (fs.FS, error) {
+	info, err := os.Stat(location)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read must-gather %q: %w", location, err)
+	}
+
+	var topFS fs.FS
+	switch {
+	case info.IsDir():
+		topFS = os.DirFS(location)
+	case strings.HasSuffix(location, ".tar.gz"), strings.HasSuffix(location, ".tgz"):
+		topFS, err = readArchive(location, true)
+	case strings.HasSuffix(location, ".tar"):
+		topFS, err = readArchive(location, false)
+	default:
+		return nil, fmt.Errorf("must-gather %q must be a directory or a .tar, .tar.gz, or .tgz archive", location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := findRoot(topFS)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find must-gather content in %q: %w", location, err)
+	}
+	if root == "." {
+		return topFS, nil
+	}
+	return fs.Sub(topFS, root)
+}
+
+func readArchive(location string, gzipped bool) (fs.FS, error) {
+	file, err := os.Open(location)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %q: %w", location, err)
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if gzipped {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress %q: %w", location, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	ret := newArchiveFS()
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %q: %w", location, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			// directories are synthesized from the files they contain.
+			continue
+		}
+		name := cleanArchivePath(header.Name)
+		if !fs.ValidPath(name) || name == "." {
+			return nil, fmt.Errorf("invalid path %q in %q", header.Name, location)
+		}
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %q from %q: %w", header.Name, location, err)
+		}
+		ret.add(name, content, fs.FileMode(header.Mode), header.ModTime)
+	}
+	ret.sortChildren()
+
+	return ret, nil
+}
+
+// isRoot returns true if dir contains the top level content of a must-gather.
+func isRoot(entries []fs.DirEntry) bool {
+	for _, entry := range entries {
+		switch entry.Name() {
+		case "cluster-scoped-resources", "namespaces", "aggregated-discovery-api.yaml", "aggregated-discovery-apis.yaml":
+			return true
+		}
+	}
+	return false
+}
+
+// findRoot returns the shallowest directory that looks like a must-gather.  If there is no such directory, "." is
+// returned so that an empty must-gather is still usable.  More than one candidate at the same depth is an error.
+func findRoot(fsys fs.FS) (string, error) {
+	candidates := []string{"."}
+	for depth := 0; depth <= maxRootDepth && len(candidates) > 0; depth++ {
+		roots := []string{}
+		subdirectories := []string{}
+		for _, candidate := range candidates {
+			entries, err := fs.ReadDir(fsys, candidate)
+			if err != nil {
+				return "", err
+			}
+			if isRoot(entries) {
+				roots = append(roots, candidate)
+				continue
+			}
+			for _, entry := range entries {
+				if entry.IsDir() {
+					subdirectories = append(subdirectories, path.Join(candidate, entry.Name()))
+				}
+			}
+		}
+		switch len(roots) {
+		case 0:
+			candidates = subdirectories
+		case 1:
+			return roots[0], nil
+		default:
+			return "", fmt.Errorf("found multiple must-gathers: %v", roots)
+		}
+	}
+
+	return ".", nil
+}
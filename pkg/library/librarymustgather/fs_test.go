@@ -0,0 +1,159 @@
+package librarymustgather
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const namespaceFile = "namespaces/openshift-config/core/configmaps.yaml"
+
+func writeArchive(t *testing.T, location string, gzipped bool, files map[string]string) {
+	t.Helper()
+
+	file, err := os.Create(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var writer io.Writer = file
+	if gzipped {
+		gzipWriter := gzip.NewWriter(file)
+		defer gzipWriter.Close()
+		writer = gzipWriter
+	}
+	tarWriter := tar.NewWriter(writer)
+	defer tarWriter.Close()
+	for name, content := range files {
+		if err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writeDirectory(t *testing.T, location string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		filename := filepath.Join(location, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestNewFS(t *testing.T) {
+	nestedFiles := map[string]string{
+		"must-gather.local.123/timestamp":                                                 "now",
+		"must-gather.local.123/quay-io-openshift-release-dev-sha256-abc/" + namespaceFile: "kind: ConfigMapList",
+		"must-gather.local.123/quay-io-openshift-release-dev-sha256-abc/timestamp":        "now",
+	}
+
+	tests := []struct {
+		name          string
+		create        func(t *testing.T, dir string) string
+		expectedError string
+	}{
+		{
+			name: "directory",
+			create: func(t *testing.T, dir string) string {
+				writeDirectory(t, dir, map[string]string{namespaceFile: "kind: ConfigMapList"})
+				return dir
+			},
+		},
+		{
+			name: "nested directory",
+			create: func(t *testing.T, dir string) string {
+				writeDirectory(t, dir, nestedFiles)
+				return dir
+			},
+		},
+		{
+			name: "nested tar.gz",
+			create: func(t *testing.T, dir string) string {
+				location := filepath.Join(dir, "must-gather.tar.gz")
+				writeArchive(t, location, true, nestedFiles)
+				return location
+			},
+		},
+		{
+			name: "tar with leading ./",
+			create: func(t *testing.T, dir string) string {
+				location := filepath.Join(dir, "must-gather.tar")
+				writeArchive(t, location, false, map[string]string{"./" + namespaceFile: "kind: ConfigMapList"})
+				return location
+			},
+		},
+		{
+			name: "multiple must-gathers",
+			create: func(t *testing.T, dir string) string {
+				writeDirectory(t, dir, map[string]string{
+					"first/" + namespaceFile:  "kind: ConfigMapList",
+					"second/" + namespaceFile: "kind: ConfigMapList",
+				})
+				return dir
+			},
+			expectedError: "found multiple must-gathers: [first second]",
+		},
+		{
+			name: "unsupported file",
+			create: func(t *testing.T, dir string) string {
+				location := filepath.Join(dir, "must-gather.zip")
+				writeDirectory(t, dir, map[string]string{"must-gather.zip": ""})
+				return location
+			},
+			expectedError: "must be a directory or a .tar, .tar.gz, or .tgz archive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location := tt.create(t, t.TempDir())
+			actual, err := NewFS(location)
+			if len(tt.expectedError) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := fs.ReadFile(actual, namespaceFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != "kind: ConfigMapList" {
+				t.Errorf("unexpected content: %q", string(content))
+			}
+		})
+	}
+}
+
+func TestArchiveFS(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "must-gather.tar")
+	writeArchive(t, location, false, map[string]string{
+		namespaceFile: "kind: ConfigMapList",
+		"cluster-scoped-resources/core/nodes.yaml": "kind: NodeList",
+		"timestamp": "now",
+	})
+	actual, err := NewFS(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fstest.TestFS(actual, namespaceFile, "cluster-scoped-resources/core/nodes.yaml", "timestamp"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,29 @@
+package libraryoutputresources
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+
+	"github.com/openshift/library-go/pkg/manifestclient"
+	"k8s.io/client-go/discovery"
+)
+
+// NewDefaultDiscoveryClient returns a discovery client backed by the discovery information embedded in manifestclient.
+// This is the same discovery used for a must-gather that does not include its own.
+func NewDefaultDiscoveryClient() (discovery.AggregatedDiscoveryInterface, error) {
+	httpClient := &http.Client{
+		Transport: manifestclient.NewTestingRoundTripper(emptyFS{}),
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfigAndClient(manifestclient.RecommendedRESTConfig(), httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating discoveryClient for NewDefaultDiscoveryClient: %w", err)
+	}
+	return discoveryClient, nil
+}
+
+type emptyFS struct{}
+
+func (emptyFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
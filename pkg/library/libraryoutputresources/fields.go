@@ -0,0 +1,89 @@
+package libraryoutputresources
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldPathSegment is one step of a parsed field path: either a child name or every item of a list.
+type FieldPathSegment struct {
+	Name     string
+	Wildcard bool
+}
+
+// ParseFieldPath parses the subset of JSONPath used to select fields: child names like .spec.foo or ['config.yaml'],
+// and [*] for every item in a list.  A leading $ is optional.  Input projections and output field ownership share it.
+func ParseFieldPath(fieldPath string) ([]FieldPathSegment, error) {
+	remaining := strings.TrimPrefix(fieldPath, "$")
+	if len(remaining) == 0 {
+		return nil, fmt.Errorf("must select a field")
+	}
+
+	segments := []FieldPathSegment{}
+	for len(remaining) > 0 {
+		switch {
+		case remaining[0] == '.':
+			remaining = remaining[1:]
+			end := strings.IndexAny(remaining, ".[")
+			if end < 0 {
+				end = len(remaining)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty field name in %q", fieldPath)
+			}
+			segments = append(segments, FieldPathSegment{Name: remaining[:end]})
+			remaining = remaining[end:]
+
+		case strings.HasPrefix(remaining, "[*]"):
+			segments = append(segments, FieldPathSegment{Wildcard: true})
+			remaining = remaining[len("[*]"):]
+
+		case strings.HasPrefix(remaining, "['") || strings.HasPrefix(remaining, `["`):
+			quote := remaining[1:2]
+			end := strings.Index(remaining[2:], quote+"]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated field name in %q", fieldPath)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty field name in %q", fieldPath)
+			}
+			segments = append(segments, FieldPathSegment{Name: remaining[2 : 2+end]})
+			remaining = remaining[2+end+2:]
+
+		default:
+			return nil, fmt.Errorf("only .name, ['name'], and [*] are supported, found %q in %q", remaining, fieldPath)
+		}
+	}
+
+	return segments, nil
+}
+
+// ParseOwnedFieldPath parses a field path of an output resource into its field names.  There is no [*], because lists
+// are owned as a whole.
+func ParseOwnedFieldPath(fieldPath string) ([]string, error) {
+	segments, err := ParseFieldPath(fieldPath)
+	if err != nil {
+		return nil, err
+	}
+	ret := []string{}
+	for _, segment := range segments {
+		if segment.Wildcard {
+			return nil, fmt.Errorf("[*] is not supported, lists are owned as a whole, found in %q", fieldPath)
+		}
+		ret = append(ret, segment.Name)
+	}
+	return ret, nil
+}
+
+// FieldPathString is the inverse of ParseOwnedFieldPath.
+func FieldPathString(segments []string) string {
+	ret := ""
+	for _, segment := range segments {
+		if strings.ContainsAny(segment, ".[]'") {
+			ret += fmt.Sprintf("[%q]", segment)
+			continue
+		}
+		ret += "." + segment
+	}
+	return ret
+}
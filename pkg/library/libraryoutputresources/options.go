@@ -2,6 +2,7 @@ package libraryoutputresources
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"k8s.io/cli-runtime/pkg/genericiooptions"
@@ -27,14 +28,22 @@ func (o *outputResourcesOptions) Run(ctx context.Context) error {
 		return err
 	}
 
+	errs := []error{}
+	discoveryClient, err := NewDefaultDiscoveryClient()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, ValidateOutputResources(result, discoveryClient)...)
+	CanonicalizeOutputResources(result)
+
 	outputResourcesYAML, err := yaml.Marshal(result)
 	if err != nil {
-		return err
+		errs = append(errs, fmt.Errorf("failed marshalling output resources: %w", err))
 	}
 
 	if _, err := fmt.Fprint(o.streams.Out, string(outputResourcesYAML)); err != nil {
-		return err
+		errs = append(errs, fmt.Errorf("failed outputing output resources: %w", err))
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
@@ -0,0 +1,196 @@
+package libraryoutputresources
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/manifestclient"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateOutputResources(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  *OutputResources
+		want []string
+	}{
+		{
+			name: "valid",
+			obj: &OutputResources{
+				ConfigurationResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						ExactConfigResource("ingresses"),
+					},
+				},
+				ManagementResources: ResourceList{
+					GeneratedNameResources: []GeneratedResourceID{
+						GeneratedResource("certificates.k8s.io", "v1", "certificatesigningrequests", "", "system:openshift:openshift-authenticator-"),
+					},
+					LabelSelectedResources: []LabelSelectedResource{
+						{
+							OutputResourceTypeIdentifier: OutputResourceTypeIdentifier{Version: "v1", Resource: "configmaps"},
+							Namespace:                    "openshift-authentication",
+							LabelSelector:                metav1.LabelSelector{MatchLabels: map[string]string{"revision": "1"}},
+						},
+					},
+					EventingNamespaces: []string{"openshift-authentication-operator"},
+				},
+				UserWorkloadResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						ExactConfigMap("openshift-authentication", "foo"),
+						// not served by the default discovery, so the namespace is not checked
+						ExactResource("example.openshift.io", "v1", "widgets", "", "cluster"),
+					},
+					EventingNamespaces: []string{"openshift-authentication"},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "missing fields",
+			obj: &OutputResources{
+				ConfigurationResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						ExactResource("", "", "secrets", "foo", ""),
+					},
+					GeneratedNameResources: []GeneratedResourceID{
+						GeneratedResource("", "v1", "", "foo", ""),
+					},
+					LabelSelectedResources: []LabelSelectedResource{
+						{
+							OutputResourceTypeIdentifier: OutputResourceTypeIdentifier{Version: "v1", Resource: "secrets"},
+							Namespace:                    "foo",
+						},
+					},
+				},
+			},
+			want: []string{
+				`configurationResources.exactResources[0].version: Required value: must be present`,
+				`configurationResources.exactResources[0].name: Required value: must be present`,
+				`configurationResources.generatedNameResources[0].resource: Required value: must be present`,
+				`configurationResources.generatedNameResources[0].name: Required value: must be present, an empty prefix would match every generated name`,
+				`configurationResources.labelSelectedResources[0].labelSelector: Required value: must select at least one label, an empty labelSelector never matches`,
+			},
+		},
+		{
+			name: "duplicates across cluster types",
+			obj: &OutputResources{
+				ConfigurationResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						ExactConfigMap("openshift-authentication", "foo"),
+					},
+					EventingNamespaces: []string{"openshift-authentication"},
+				},
+				UserWorkloadResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						ExactConfigMap("openshift-authentication", "foo"),
+					},
+					EventingNamespaces: []string{"openshift-authentication"},
+				},
+			},
+			want: []string{
+				`userWorkloadResources.exactResources[0]: Duplicate value: "configmaps.v1 openshift-authentication/foo already declared by configurationResources.exactResources[0]"`,
+				`userWorkloadResources.eventingNamespaces[0]: Duplicate value: "openshift-authentication already declared by configurationResources.eventingNamespaces[0]"`,
+			},
+		},
+		{
+			name: "scope and eventing namespaces",
+			obj: &OutputResources{
+				ManagementResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						ExactResource("config.openshift.io", "v1", "ingresses", "openshift-config", "cluster"),
+						ExactResource("", "v1", "secrets", "", "serving-cert"),
+					},
+					EventingNamespaces: []string{"Not_A_Namespace"},
+				},
+			},
+			want: []string{
+				`managementResources.eventingNamespaces[0]: Invalid value: "Not_A_Namespace": a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`,
+				`managementResources.exactResources[0].namespace: Forbidden: ingresses.v1.config.openshift.io is cluster-scoped`,
+				`managementResources.exactResources[1].namespace: Required value: secrets.v1 is namespaced`,
+			},
+		},
+		{
+			name: "bad actions",
+			obj: &OutputResources{
+				ManagementResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						{
+							OutputResourceTypeIdentifier: OutputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"},
+							Name:                         "authentication",
+							Actions:                      []manifestclient.Action{manifestclient.ActionApplyStatus, "Get", manifestclient.ActionApplyStatus},
+						},
+					},
+				},
+			},
+			want: []string{
+				`managementResources.exactResources[0].actions[1]: Unsupported value: "Get": supported values: "Apply", "ApplyStatus", "Create", "Delete", "Patch", "PatchStatus", "Update", "UpdateStatus"`,
+				`managementResources.exactResources[0].actions[2]: Duplicate value: "ApplyStatus"`,
+			},
+		},
+		{
+			name: "bad fields",
+			obj: &OutputResources{
+				ConfigurationResources: ResourceList{
+					ExactResources: []ExactResourceID{
+						{
+							OutputResourceTypeIdentifier: OutputResourceTypeIdentifier{Group: "config.openshift.io", Version: "v1", Resource: "ingresses"},
+							Name:                         "cluster",
+							Fields:                       []string{".spec.componentRoutes", "$.metadata.annotations['example.openshift.io/owner']", "spec", ".spec.routes[*]", ".spec..domain"},
+						},
+					},
+				},
+			},
+			want: []string{
+				`configurationResources.exactResources[0].fields[2]: Invalid value: "spec": only .name, ['name'], and [*] are supported, found "spec" in "spec"`,
+				`configurationResources.exactResources[0].fields[3]: Invalid value: ".spec.routes[*]": [*] is not supported, lists are owned as a whole, found in ".spec.routes[*]"`,
+				`configurationResources.exactResources[0].fields[4]: Invalid value: ".spec..domain": empty field name in ".spec..domain"`,
+			},
+		},
+	}
+
+	discoveryClient, err := NewDefaultDiscoveryClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateOutputResources(tt.obj, discoveryClient)
+			actualStrings := []string{}
+			for _, curr := range errs {
+				actualStrings = append(actualStrings, curr.Error())
+			}
+			if !reflect.DeepEqual(actualStrings, tt.want) {
+				t.Errorf("ValidateOutputResources() = %v", actualStrings)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeOutputResources(t *testing.T) {
+	obj := &OutputResources{
+		ManagementResources: ResourceList{
+			ExactResources: []ExactResourceID{
+				ExactSecret("openshift-config", "b"),
+				ExactConfigMap("openshift-config", "z"),
+				ExactConfigResource("ingresses"),
+				ExactSecret("openshift-config", "a"),
+			},
+			EventingNamespaces: []string{"openshift-b", "openshift-a"},
+		},
+	}
+	CanonicalizeOutputResources(obj)
+
+	expected := []ExactResourceID{
+		ExactConfigMap("openshift-config", "z"),
+		ExactSecret("openshift-config", "a"),
+		ExactSecret("openshift-config", "b"),
+		ExactConfigResource("ingresses"),
+	}
+	if !reflect.DeepEqual(expected, obj.ManagementResources.ExactResources) {
+		t.Errorf("unexpected order: %v", obj.ManagementResources.ExactResources)
+	}
+	if !reflect.DeepEqual([]string{"openshift-a", "openshift-b"}, obj.ManagementResources.EventingNamespaces) {
+		t.Errorf("unexpected order: %v", obj.ManagementResources.EventingNamespaces)
+	}
+}
@@ -1,5 +1,12 @@
 package libraryoutputresources
 
+import (
+	"slices"
+
+	"github.com/openshift/library-go/pkg/manifestclient"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // OutputResources is a list of resources that an operator will need to mutate from apply-configuration and apply-configuration-live.
 // This needs to be a complete list.  Any resource not present in this list will not be mutable for this operator.
 type OutputResources struct {
@@ -32,8 +39,25 @@ type ResourceList struct {
 	// to the userWorkload cluster.
 	EventingNamespaces []string `json:"eventingNamespaces,omitempty"`
 
-	// TODO I bet this covers 95% of what we need, but maybe we need label selector.
+	// labelSelectedResources are families of resources identified by their labels, like the revisioned configmaps and
+	// secrets of a RevisionController.  Only Apply, Create, and Update mutations match, using the labels in their body.
+	// Patches never match, even if they set the labels.  Deletes never match either, so pruning old revisions is always
+	// reported as unspecified output unless the deleted resources are listed by name.
 	// I'm a solid -1 on "pattern" based selection. We select in kube based on label selectors.
+	LabelSelectedResources []LabelSelectedResource `json:"labelSelectedResources,omitempty"`
+}
+
+type LabelSelectedResource struct {
+	OutputResourceTypeIdentifier `json:",inline"`
+
+	// namespace must match the namespace of the mutated resource.  It is empty for cluster-scoped resources.
+	Namespace string `json:"namespace,omitempty"`
+
+	// labelSelector supports both matchLabels and matchExpressions.  An empty labelSelector never matches, because it
+	// would allow mutating every resource of the type.
+	// There are no fields, label-selected resources are owned whole.  List a shared resource in exactResources to
+	// restrict the fields that may be set on it.
+	LabelSelector metav1.LabelSelector `json:"labelSelector"`
 }
 
 type ExactResourceID struct {
@@ -41,6 +65,16 @@ type ExactResourceID struct {
 
 	Namespace string `json:"namespace,omitempty"`
 	Name      string `json:"name"`
+
+	// actions optionally restricts the mutations allowed on this resource, for instance only ApplyStatus on an operator
+	// resource.  When empty, every action is allowed.  Mutations with other actions are treated as unspecified output.
+	Actions []manifestclient.Action `json:"actions,omitempty"`
+
+	// fields optionally restricts the fields that Apply, Patch, and Update mutations may set on a shared resource, like
+	// .spec.defaultCertificate or .metadata.annotations['example.openshift.io/owner'].  A field owns everything below
+	// it and lists are owned as a whole.  Identity metadata like name and namespace is always allowed.
+	// When empty, every field may be set.
+	Fields []string `json:"fields,omitempty"`
 }
 
 type GeneratedResourceID struct {
@@ -48,6 +82,17 @@ type GeneratedResourceID struct {
 
 	Namespace     string `json:"namespace,omitempty"`
 	GeneratedName string `json:"name"`
+
+	// actions optionally restricts the mutations allowed on these resources.  See ExactResourceID.Actions.
+	Actions []manifestclient.Action `json:"actions,omitempty"`
+
+	// fields optionally restricts the fields that mutations may set.  See ExactResourceID.Fields.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// AllowsAction returns true if action is allowed by actions, an empty list allows every action.
+func AllowsAction(actions []manifestclient.Action, action manifestclient.Action) bool {
+	return len(actions) == 0 || slices.Contains(actions, action)
 }
 
 // OutputResourceTypeIdentifier does *not* include version, because the serialization doesn't matter for production.
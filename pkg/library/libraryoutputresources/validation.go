@@ -0,0 +1,238 @@
+package libraryoutputresources
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/manifestclient"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/discovery"
+)
+
+type resourceListLocation struct {
+	fieldPath    *field.Path
+	resourceList ResourceList
+}
+
+func resourceListsForOutputResources(obj *OutputResources) []resourceListLocation {
+	return []resourceListLocation{
+		{fieldPath: field.NewPath("configurationResources"), resourceList: obj.ConfigurationResources},
+		{fieldPath: field.NewPath("managementResources"), resourceList: obj.ManagementResources},
+		{fieldPath: field.NewPath("userWorkloadResources"), resourceList: obj.UserWorkloadResources},
+	}
+}
+
+// ValidateOutputResources checks that every entry is complete and that no resource or eventing namespace is declared
+// more than once, since each output must be sent to exactly one cluster type.
+// If discoveryClient is not nil, resources it serves are also checked for namespaces that do not match their scope.
+// Resources that discoveryClient does not serve are allowed, operators may output resources for their own CRDs.
+func ValidateOutputResources(obj *OutputResources, discoveryClient discovery.AggregatedDiscoveryInterface) []error {
+	errs := []error{}
+
+	// entries are keyed by what they match, so the same entry in two places is found regardless of the cluster type.
+	declaredAt := map[string]*field.Path{}
+	checkDuplicate := func(path *field.Path, key string, value interface{}) {
+		if existing, ok := declaredAt[key]; ok {
+			errs = append(errs, field.Duplicate(path, fmt.Sprintf("%v already declared by %v", value, existing)))
+			return
+		}
+		declaredAt[key] = path
+	}
+
+	for _, currList := range resourceListsForOutputResources(obj) {
+		for i, curr := range currList.resourceList.ExactResources {
+			currPath := currList.fieldPath.Child("exactResources").Index(i)
+			errs = append(errs, validateOutputResourceTypeIdentifier(currPath, curr.OutputResourceTypeIdentifier)...)
+			if len(curr.Name) == 0 {
+				errs = append(errs, field.Required(currPath.Child("name"), "must be present"))
+			}
+			errs = append(errs, validateActions(currPath.Child("actions"), curr.Actions)...)
+			errs = append(errs, validateFields(currPath.Child("fields"), curr.Fields)...)
+			checkDuplicate(currPath, fmt.Sprintf("exact/%s/%s/%s/%s", curr.Group, curr.Resource, curr.Namespace, curr.Name), resourceString(curr.OutputResourceTypeIdentifier, curr.Namespace, curr.Name))
+		}
+		for i, curr := range currList.resourceList.GeneratedNameResources {
+			currPath := currList.fieldPath.Child("generatedNameResources").Index(i)
+			errs = append(errs, validateOutputResourceTypeIdentifier(currPath, curr.OutputResourceTypeIdentifier)...)
+			if len(curr.GeneratedName) == 0 {
+				errs = append(errs, field.Required(currPath.Child("name"), "must be present, an empty prefix would match every generated name"))
+			}
+			errs = append(errs, validateActions(currPath.Child("actions"), curr.Actions)...)
+			errs = append(errs, validateFields(currPath.Child("fields"), curr.Fields)...)
+			checkDuplicate(currPath, fmt.Sprintf("generated/%s/%s/%s/%s", curr.Group, curr.Resource, curr.Namespace, curr.GeneratedName), resourceString(curr.OutputResourceTypeIdentifier, curr.Namespace, curr.GeneratedName+"*"))
+		}
+		for i, curr := range currList.resourceList.LabelSelectedResources {
+			currPath := currList.fieldPath.Child("labelSelectedResources").Index(i)
+			errs = append(errs, validateOutputResourceTypeIdentifier(currPath, curr.OutputResourceTypeIdentifier)...)
+			for _, err := range metav1validation.ValidateLabelSelector(&curr.LabelSelector, metav1validation.LabelSelectorValidationOptions{}, currPath.Child("labelSelector")) {
+				errs = append(errs, err)
+			}
+			if len(curr.LabelSelector.MatchLabels) == 0 && len(curr.LabelSelector.MatchExpressions) == 0 {
+				errs = append(errs, field.Required(currPath.Child("labelSelector"), "must select at least one label, an empty labelSelector never matches"))
+			}
+			selector := metav1.FormatLabelSelector(&curr.LabelSelector)
+			checkDuplicate(currPath, fmt.Sprintf("labelSelected/%s/%s/%s/%s", curr.Group, curr.Resource, curr.Namespace, selector), resourceString(curr.OutputResourceTypeIdentifier, curr.Namespace, selector))
+		}
+		for i, curr := range currList.resourceList.EventingNamespaces {
+			currPath := currList.fieldPath.Child("eventingNamespaces").Index(i)
+			for _, msg := range validation.IsDNS1123Label(curr) {
+				errs = append(errs, field.Invalid(currPath, curr, msg))
+			}
+			checkDuplicate(currPath, fmt.Sprintf("eventing/%s", curr), curr)
+		}
+	}
+
+	if discoveryClient != nil {
+		errs = append(errs, validateOutputResourcesAgainstDiscovery(obj, discoveryClient)...)
+	}
+
+	return errs
+}
+
+func validateOutputResourceTypeIdentifier(path *field.Path, obj OutputResourceTypeIdentifier) []error {
+	errs := []error{}
+
+	if len(obj.Version) == 0 {
+		errs = append(errs, field.Required(path.Child("version"), "must be present"))
+	}
+	if len(obj.Resource) == 0 {
+		errs = append(errs, field.Required(path.Child("resource"), "must be present"))
+	}
+
+	return errs
+}
+
+func validateActions(path *field.Path, actions []manifestclient.Action) []error {
+	errs := []error{}
+
+	seen := sets.New[manifestclient.Action]()
+	for i, curr := range actions {
+		switch {
+		case !manifestclient.AllActions.Has(curr):
+			errs = append(errs, field.NotSupported(path.Index(i), curr, sets.List(manifestclient.AllActions)))
+		case seen.Has(curr):
+			errs = append(errs, field.Duplicate(path.Index(i), curr))
+		}
+		seen.Insert(curr)
+	}
+
+	return errs
+}
+
+func validateFields(path *field.Path, fields []string) []error {
+	errs := []error{}
+
+	for i, curr := range fields {
+		if _, err := ParseOwnedFieldPath(curr); err != nil {
+			errs = append(errs, field.Invalid(path.Index(i), curr, err.Error()))
+		}
+	}
+
+	return errs
+}
+
+func validateOutputResourcesAgainstDiscovery(obj *OutputResources, discoveryClient discovery.AggregatedDiscoveryInterface) []error {
+	_, gvToAPIResourceList, _, err := discoveryClient.GroupsAndMaybeResources()
+	if err != nil {
+		return []error{fmt.Errorf("failed to get api resource list with GroupsAndMaybeResources: %w", err)}
+	}
+	namespacedByResource := map[schema.GroupVersionResource]bool{}
+	for gv, apiResourceList := range gvToAPIResourceList {
+		for _, apiResource := range apiResourceList.APIResources {
+			if strings.Contains(apiResource.Name, "/") {
+				// Skip subresources
+				continue
+			}
+			namespacedByResource[gv.WithResource(apiResource.Name)] = apiResource.Namespaced
+		}
+	}
+
+	errs := []error{}
+	checkScope := func(path *field.Path, identifier OutputResourceTypeIdentifier, namespace string) {
+		gvr := schema.GroupVersionResource{Group: identifier.Group, Version: identifier.Version, Resource: identifier.Resource}
+		namespaced, ok := namespacedByResource[gvr]
+		switch {
+		case !ok:
+		case namespaced && len(namespace) == 0:
+			errs = append(errs, field.Required(path.Child("namespace"), fmt.Sprintf("%s is namespaced", resourceTypeString(gvr))))
+		case !namespaced && len(namespace) > 0:
+			errs = append(errs, field.Forbidden(path.Child("namespace"), fmt.Sprintf("%s is cluster-scoped", resourceTypeString(gvr))))
+		}
+	}
+	for _, currList := range resourceListsForOutputResources(obj) {
+		for i, curr := range currList.resourceList.ExactResources {
+			checkScope(currList.fieldPath.Child("exactResources").Index(i), curr.OutputResourceTypeIdentifier, curr.Namespace)
+		}
+		for i, curr := range currList.resourceList.GeneratedNameResources {
+			checkScope(currList.fieldPath.Child("generatedNameResources").Index(i), curr.OutputResourceTypeIdentifier, curr.Namespace)
+		}
+		for i, curr := range currList.resourceList.LabelSelectedResources {
+			checkScope(currList.fieldPath.Child("labelSelectedResources").Index(i), curr.OutputResourceTypeIdentifier, curr.Namespace)
+		}
+	}
+
+	return errs
+}
+
+func resourceTypeString(gvr schema.GroupVersionResource) string {
+	if len(gvr.Group) == 0 {
+		return fmt.Sprintf("%s.%s", gvr.Resource, gvr.Version)
+	}
+	return fmt.Sprintf("%s.%s.%s", gvr.Resource, gvr.Version, gvr.Group)
+}
+
+func resourceString(identifier OutputResourceTypeIdentifier, namespace, name string) string {
+	gvr := schema.GroupVersionResource{Group: identifier.Group, Version: identifier.Version, Resource: identifier.Resource}
+	if len(namespace) == 0 {
+		return fmt.Sprintf("%s %s", resourceTypeString(gvr), name)
+	}
+	return fmt.Sprintf("%s %s/%s", resourceTypeString(gvr), namespace, name)
+}
+
+// CanonicalizeOutputResources sorts every list in obj so that the serialized form only changes when the content does.
+func CanonicalizeOutputResources(obj *OutputResources) {
+	if obj == nil {
+		return
+	}
+	for _, resourceList := range []*ResourceList{&obj.ConfigurationResources, &obj.ManagementResources, &obj.UserWorkloadResources} {
+		slices.SortStableFunc(resourceList.ExactResources, func(a, b ExactResourceID) int {
+			return compareResources(a.OutputResourceTypeIdentifier, a.Namespace, a.Name, b.OutputResourceTypeIdentifier, b.Namespace, b.Name)
+		})
+		slices.SortStableFunc(resourceList.GeneratedNameResources, func(a, b GeneratedResourceID) int {
+			return compareResources(a.OutputResourceTypeIdentifier, a.Namespace, a.GeneratedName, b.OutputResourceTypeIdentifier, b.Namespace, b.GeneratedName)
+		})
+		slices.SortStableFunc(resourceList.LabelSelectedResources, func(a, b LabelSelectedResource) int {
+			return compareResources(a.OutputResourceTypeIdentifier, a.Namespace, metav1.FormatLabelSelector(&a.LabelSelector), b.OutputResourceTypeIdentifier, b.Namespace, metav1.FormatLabelSelector(&b.LabelSelector))
+		})
+		for i := range resourceList.ExactResources {
+			slices.Sort(resourceList.ExactResources[i].Actions)
+			slices.Sort(resourceList.ExactResources[i].Fields)
+		}
+		for i := range resourceList.GeneratedNameResources {
+			slices.Sort(resourceList.GeneratedNameResources[i].Actions)
+			slices.Sort(resourceList.GeneratedNameResources[i].Fields)
+		}
+		slices.Sort(resourceList.EventingNamespaces)
+	}
+}
+
+func compareResources(aType OutputResourceTypeIdentifier, aNamespace, aName string, bType OutputResourceTypeIdentifier, bNamespace, bName string) int {
+	if c := strings.Compare(aType.Group, bType.Group); c != 0 {
+		return c
+	}
+	if c := strings.Compare(aType.Resource, bType.Resource); c != 0 {
+		return c
+	}
+	if c := strings.Compare(aType.Version, bType.Version); c != 0 {
+		return c
+	}
+	if c := strings.Compare(aNamespace, bNamespace); c != 0 {
+		return c
+	}
+	return strings.Compare(aName, bName)
+}
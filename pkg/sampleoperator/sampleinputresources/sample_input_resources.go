@@ -18,6 +18,10 @@ func SampleRunInputResources(ctx context.Context) (*libraryinputresources.InputR
 				libraryinputresources.ExactConfigResource("oauths"),
 				libraryinputresources.ExactConfigMap("openshift-authentication", "fail-check"),
 				libraryinputresources.ExactConfigMap("openshift-authentication", "foo"),
+				// read by CreateOperatorStarter to seed the versions of the status controller.
+				libraryinputresources.ExactClusterOperator("example"),
+				// read by the dynamic informer of the operator client.
+				libraryinputresources.ExactLowLevelOperator("authentications"),
 			},
 			LabelSelectedResources: []libraryinputresources.LabelSelectedResource{
 				{
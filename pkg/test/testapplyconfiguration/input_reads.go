@@ -0,0 +1,322 @@
+package testapplyconfiguration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryapplyconfiguration"
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryinputresources"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+)
+
+// UndeclaredInputReads returns the reads that are not covered by the applyConfigurationResources of inputResources.
+// inputDir is used to resolve resourceReferences, so a GET of a referenced resource is declared if it is present.
+// A LIST is declared if it matches a declared labelSelector or if every resource it returns from inputDir is declared.
+func UndeclaredInputReads(ctx context.Context, inputReads *libraryapplyconfiguration.ApplyConfigurationInputReads, inputResources *libraryinputresources.InputResources, inputDir string) (*libraryapplyconfiguration.ApplyConfigurationInputReads, error) {
+	if inputReads == nil {
+		return nil, nil
+	}
+
+	inputDirClient, err := libraryinputresources.NewDynamicClientFromMustGather(inputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading %q: %w", inputDir, err)
+	}
+	resolvedResources, err := libraryinputresources.GetRequiredInputResourcesFromClient(ctx, inputResources, inputDirClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving input resources in %q: %w", inputDir, err)
+	}
+	resolvedResourceKeys := sets.New[string]()
+	for _, curr := range resolvedResources {
+		// resources are in must-gather format, so every file holds a list.
+		err := curr.Content.EachListItem(func(obj runtime.Object) error {
+			item := obj.(*unstructured.Unstructured)
+			resolvedResourceKeys.Insert(resourceKey(curr.ResourceType.Group, curr.ResourceType.Version, curr.ResourceType.Resource, item.GetNamespace(), item.GetName()))
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed reading %v: %w", curr.Filename, err)
+		}
+	}
+
+	ret := &libraryapplyconfiguration.ApplyConfigurationInputReads{}
+	for _, controllerReads := range inputReads.ControllerReads {
+		undeclaredReads := []libraryapplyconfiguration.InputRead{}
+		for _, read := range controllerReads.Reads {
+			declared, err := isReadDeclared(read, inputResources.ApplyConfigurationResources, resolvedResourceKeys)
+			if err != nil {
+				return nil, err
+			}
+			if !declared && len(read.Name) == 0 {
+				declared, err = listReturnsOnlyDeclared(ctx, inputDirClient, read, resolvedResourceKeys)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if !declared {
+				undeclaredReads = append(undeclaredReads, read)
+			}
+		}
+		if len(undeclaredReads) == 0 {
+			continue
+		}
+		ret.ControllerReads = append(ret.ControllerReads, libraryapplyconfiguration.ControllerInputReads{
+			ControllerName: controllerReads.ControllerName,
+			Reads:          undeclaredReads,
+		})
+	}
+
+	return ret, nil
+}
+
+func resourceKey(group, version, resource, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s", group, version, resource, namespace, name)
+}
+
+// listReturnsOnlyDeclared returns true if every resource the list returns from the input-dir is declared, so the list
+// returns the same resources when the MOM only provides declared inputs.  Informers list whole namespaces, but only
+// the resources they return need to be declared.
+func listReturnsOnlyDeclared(ctx context.Context, inputDirClient dynamic.Interface, read libraryapplyconfiguration.InputRead, resolvedResourceKeys sets.Set[string]) (bool, error) {
+	gvr := schema.GroupVersionResource{Group: read.Group, Version: read.Version, Resource: read.Resource}
+	list, err := inputDirClient.Resource(gvr).Namespace(read.Namespace).List(ctx, metav1.ListOptions{LabelSelector: read.LabelSelector})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed listing %v: %w", read, err)
+	}
+	for _, curr := range list.Items {
+		if !resolvedResourceKeys.Has(resourceKey(read.Group, read.Version, read.Resource, curr.GetNamespace(), curr.GetName())) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func isReadDeclared(read libraryapplyconfiguration.InputRead, resourceList libraryinputresources.ResourceList, resolvedResourceKeys sets.Set[string]) (bool, error) {
+	sameType := func(identifier libraryinputresources.InputResourceTypeIdentifier) bool {
+		return identifier.Group == read.Group && identifier.Version == read.Version && identifier.Resource == read.Resource
+	}
+
+	// a list restricted to a single name is treated like a get
+	if len(read.Name) > 0 {
+		if resolvedResourceKeys.Has(resourceKey(read.Group, read.Version, read.Resource, read.Namespace, read.Name)) {
+			return true, nil
+		}
+		for _, curr := range resourceList.ExactResources {
+			if sameType(curr.InputResourceTypeIdentifier) && curr.Namespace == read.Namespace && curr.Name == read.Name {
+				return true, nil
+			}
+		}
+		for _, curr := range resourceList.GeneratedNameResources {
+			if sameType(curr.InputResourceTypeIdentifier) && curr.Namespace == read.Namespace && strings.HasPrefix(read.Name, curr.GeneratedName) {
+				return true, nil
+			}
+		}
+		for _, curr := range resourceList.OwnedResources {
+			if sameType(curr.Owner.InputResourceTypeIdentifier) && curr.Owner.Namespace == read.Namespace && curr.Owner.Name == read.Name {
+				return true, nil
+			}
+		}
+	}
+
+	// dependents are found by listing every ownedResourceType in the namespace, so the same list is declared.
+	// Named gets are only declared for dependents that are actually owned, which are in resolvedResourceKeys.
+	for _, curr := range resourceList.OwnedResources {
+		if len(read.Name) > 0 || len(read.LabelSelector) > 0 || read.Namespace != ownedResourceNamespace(curr) {
+			continue
+		}
+		for _, ownedResourceType := range curr.OwnedResourceTypes {
+			if sameType(ownedResourceType) {
+				return true, nil
+			}
+		}
+	}
+
+	for _, curr := range resourceList.LabelSelectedResources {
+		if !sameType(curr.InputResourceTypeIdentifier) {
+			continue
+		}
+		if len(curr.Namespace) > 0 && curr.Namespace != read.Namespace {
+			continue
+		}
+		if len(read.Name) > 0 {
+			// a named resource with matching labels is in resolvedResourceKeys.
+			continue
+		}
+		declaredSelector, err := metav1.LabelSelectorAsSelector(&curr.LabelSelector)
+		if err != nil {
+			return false, fmt.Errorf("failed parsing labelSelector for %v: %w", curr.Name, err)
+		}
+		readSelector, err := labels.Parse(read.LabelSelector)
+		if err != nil {
+			return false, fmt.Errorf("failed parsing labelSelector %q: %w", read.LabelSelector, err)
+		}
+		// an empty labelSelector never matches, see convertOutputToInput.
+		if !declaredSelector.Empty() && declaredSelector.String() == readSelector.String() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func undeclaredInputReadsMessage(undeclaredReads *libraryapplyconfiguration.ApplyConfigurationInputReads) string {
+	lines := []string{}
+	for _, controllerReads := range undeclaredReads.ControllerReads {
+		controllerName := controllerReads.ControllerName
+		if len(controllerName) == 0 {
+			controllerName = "<no controller>"
+		}
+		for _, read := range controllerReads.Reads {
+			lines = append(lines, fmt.Sprintf("controller %q: %v", controllerName, read))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// UnreadInputResources lists the applyConfigurationResources of a binary that no controller read in any test.
+// Over-declared inputs bloat every pruned must-gather and widen the RBAC needed to collect them.
+type UnreadInputResources struct {
+	BinaryName string `json:"binaryName"`
+	// UnreadResources are only meaningful across a whole corpus of tests, because each test may only run some controllers.
+	UnreadResources []UnreadInputResource `json:"unreadResources"`
+}
+
+type UnreadInputResource struct {
+	// FieldPath is the path to the rule in the InputResources that was never read.
+	FieldPath string `json:"fieldPath"`
+
+	ExactResource         *libraryinputresources.ExactResourceID       `json:"exactResource,omitempty"`
+	GeneratedNameResource *libraryinputresources.GeneratedResourceID   `json:"generatedNameResource,omitempty"`
+	LabelSelectedResource *libraryinputresources.LabelSelectedResource `json:"labelSelectedResource,omitempty"`
+	ResourceReference     *libraryinputresources.ResourceReference     `json:"resourceReference,omitempty"`
+	OwnedResource         *libraryinputresources.OwnedResource         `json:"ownedResource,omitempty"`
+}
+
+// UnreadApplyConfigurationResources returns the rules in the applyConfigurationResources of inputResources that are
+// not covered by any of the allInputReads.  A rule is considered read when any read could have returned a resource it
+// selects, so lists of a whole namespace count as reading every resource in it.
+func UnreadApplyConfigurationResources(allInputReads []*libraryapplyconfiguration.ApplyConfigurationInputReads, inputResources *libraryinputresources.InputResources) []UnreadInputResource {
+	reads := []libraryapplyconfiguration.InputRead{}
+	for _, inputReads := range allInputReads {
+		if inputReads == nil {
+			continue
+		}
+		for _, controllerReads := range inputReads.ControllerReads {
+			reads = append(reads, controllerReads.Reads...)
+		}
+	}
+	anyRead := func(matches func(read libraryapplyconfiguration.InputRead) bool) bool {
+		for _, read := range reads {
+			if matches(read) {
+				return true
+			}
+		}
+		return false
+	}
+
+	ret := []UnreadInputResource{}
+	path := field.NewPath("applyConfigurationResources")
+	resourceList := inputResources.ApplyConfigurationResources
+	for i := range resourceList.ExactResources {
+		curr := resourceList.ExactResources[i]
+		if anyRead(func(read libraryapplyconfiguration.InputRead) bool {
+			return readCoversNamespace(read, curr.InputResourceTypeIdentifier, curr.Namespace) && (len(read.Name) == 0 || read.Name == curr.Name)
+		}) {
+			continue
+		}
+		ret = append(ret, UnreadInputResource{FieldPath: path.Child("exactResources").Index(i).String(), ExactResource: &curr})
+	}
+	for i := range resourceList.GeneratedNameResources {
+		curr := resourceList.GeneratedNameResources[i]
+		if anyRead(func(read libraryapplyconfiguration.InputRead) bool {
+			return readCoversNamespace(read, curr.InputResourceTypeIdentifier, curr.Namespace) && (len(read.Name) == 0 || strings.HasPrefix(read.Name, curr.GeneratedName))
+		}) {
+			continue
+		}
+		ret = append(ret, UnreadInputResource{FieldPath: path.Child("generatedNameResources").Index(i).String(), GeneratedNameResource: &curr})
+	}
+	for i := range resourceList.LabelSelectedResources {
+		curr := resourceList.LabelSelectedResources[i]
+		if anyRead(func(read libraryapplyconfiguration.InputRead) bool {
+			// a labelSelectedResource without a namespace selects from every namespace, so any namespace is a partial read.
+			return readHasType(read, curr.InputResourceTypeIdentifier) && (len(curr.Namespace) == 0 || len(read.Namespace) == 0 || read.Namespace == curr.Namespace)
+		}) {
+			continue
+		}
+		ret = append(ret, UnreadInputResource{FieldPath: path.Child("labelSelectedResources").Index(i).String(), LabelSelectedResource: &curr})
+	}
+	for i := range resourceList.ResourceReferences {
+		curr := resourceList.ResourceReferences[i]
+		var matches func(read libraryapplyconfiguration.InputRead) bool
+		switch {
+		case curr.ImplicitNamespacedReference != nil:
+			matches = func(read libraryapplyconfiguration.InputRead) bool {
+				return readCoversNamespace(read, curr.ImplicitNamespacedReference.InputResourceTypeIdentifier, curr.ImplicitNamespacedReference.Namespace)
+			}
+		case curr.ExplicitNamespacedReference != nil:
+			matches = func(read libraryapplyconfiguration.InputRead) bool {
+				return readHasType(read, curr.ExplicitNamespacedReference.InputResourceTypeIdentifier)
+			}
+		case curr.ClusterScopedReference != nil:
+			matches = func(read libraryapplyconfiguration.InputRead) bool {
+				return readHasType(read, curr.ClusterScopedReference.InputResourceTypeIdentifier)
+			}
+		default:
+			continue
+		}
+		if anyRead(matches) {
+			continue
+		}
+		ret = append(ret, UnreadInputResource{FieldPath: path.Child("resourceReferences").Index(i).String(), ResourceReference: &curr})
+	}
+
+	for i := range resourceList.OwnedResources {
+		curr := resourceList.OwnedResources[i]
+		if anyRead(func(read libraryapplyconfiguration.InputRead) bool {
+			if readCoversNamespace(read, curr.Owner.InputResourceTypeIdentifier, curr.Owner.Namespace) && (len(read.Name) == 0 || read.Name == curr.Owner.Name) {
+				return true
+			}
+			for _, ownedResourceType := range curr.OwnedResourceTypes {
+				if readCoversNamespace(read, ownedResourceType, ownedResourceNamespace(curr)) {
+					return true
+				}
+			}
+			return false
+		}) {
+			continue
+		}
+		ret = append(ret, UnreadInputResource{FieldPath: path.Child("ownedResources").Index(i).String(), OwnedResource: &curr})
+	}
+
+	return ret
+}
+
+// ownedResourceNamespace is the namespace where the dependents of the ownedResource are listed.
+func ownedResourceNamespace(ownedResource libraryinputresources.OwnedResource) string {
+	if len(ownedResource.Namespace) > 0 {
+		return ownedResource.Namespace
+	}
+	return ownedResource.Owner.Namespace
+}
+
+func readHasType(read libraryapplyconfiguration.InputRead, identifier libraryinputresources.InputResourceTypeIdentifier) bool {
+	return identifier.Group == read.Group && identifier.Version == read.Version && identifier.Resource == read.Resource
+}
+
+// readCoversNamespace returns true if the read has the type and could include resources from the namespace.
+func readCoversNamespace(read libraryapplyconfiguration.InputRead, identifier libraryinputresources.InputResourceTypeIdentifier, namespace string) bool {
+	if !readHasType(read, identifier) {
+		return false
+	}
+	return read.Namespace == namespace || (read.Verb == "list" && len(read.Namespace) == 0)
+}
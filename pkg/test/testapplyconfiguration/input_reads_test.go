@@ -0,0 +1,167 @@
+package testapplyconfiguration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryapplyconfiguration"
+	"github.com/openshift/multi-operator-manager/pkg/library/libraryinputresources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestUndeclaredInputReads(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFile := func(filename, content string) {
+		t.Helper()
+		filename = filepath.Join(inputDir, filename)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("namespaces/openshift-authentication/core/configmaps.yaml", `apiVersion: v1
+kind: ConfigMapList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: declared
+    namespace: openshift-authentication
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: revision-1
+    namespace: openshift-authentication
+    labels:
+      revision: "1"
+`)
+	writeFile("namespaces/openshift-config/core/configmaps.yaml", `apiVersion: v1
+kind: ConfigMapList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: declared
+    namespace: openshift-config
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: undeclared
+    namespace: openshift-config
+`)
+
+	inputResources := &libraryinputresources.InputResources{
+		ApplyConfigurationResources: libraryinputresources.ResourceList{
+			ExactResources: []libraryinputresources.ExactResourceID{
+				libraryinputresources.ExactConfigMap("openshift-authentication", "declared"),
+				libraryinputresources.ExactConfigMap("openshift-config", "declared"),
+			},
+			LabelSelectedResources: []libraryinputresources.LabelSelectedResource{
+				{
+					InputResourceTypeIdentifier: libraryinputresources.InputResourceTypeIdentifier{Version: "v1", Resource: "configmaps"},
+					Namespace:                   "openshift-authentication",
+					LabelSelector:               metav1.LabelSelector{MatchLabels: map[string]string{"revision": "1"}},
+				},
+			},
+		},
+	}
+	configMapRead := func(verb, namespace, name string) libraryapplyconfiguration.InputRead {
+		return libraryapplyconfiguration.InputRead{Verb: verb, Version: "v1", Resource: "configmaps", Namespace: namespace, Name: name}
+	}
+	inputReads := &libraryapplyconfiguration.ApplyConfigurationInputReads{
+		ControllerReads: []libraryapplyconfiguration.ControllerInputReads{
+			{
+				ControllerName: "example",
+				Reads: []libraryapplyconfiguration.InputRead{
+					// every configmap in the namespace is declared
+					configMapRead("list", "openshift-authentication", ""),
+					// selected by labels
+					configMapRead("get", "openshift-authentication", "revision-1"),
+					// not selected by labels
+					configMapRead("get", "openshift-authentication", "revision-2"),
+					// returns an undeclared configmap
+					configMapRead("list", "openshift-config", ""),
+					// nothing to return
+					configMapRead("list", "openshift-etcd", ""),
+				},
+			},
+		},
+	}
+
+	actual, err := UndeclaredInputReads(context.Background(), inputReads, inputResources, inputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &libraryapplyconfiguration.ApplyConfigurationInputReads{
+		ControllerReads: []libraryapplyconfiguration.ControllerInputReads{
+			{
+				ControllerName: "example",
+				Reads: []libraryapplyconfiguration.InputRead{
+					configMapRead("get", "openshift-authentication", "revision-2"),
+					configMapRead("list", "openshift-config", ""),
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("unexpected undeclared reads: %v", undeclaredInputReadsMessage(actual))
+	}
+}
+
+func TestUndeclaredInputReadsOwnedResources(t *testing.T) {
+	testDir := filepath.Join("..", "..", "library", "libraryinputresources", "test-data", "owned-resources-01")
+	inputResourcesBytes, err := os.ReadFile(filepath.Join(testDir, "input-resources.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputResources := &libraryinputresources.InputResources{}
+	if err := yaml.Unmarshal(inputResourcesBytes, inputResources); err != nil {
+		t.Fatal(err)
+	}
+
+	podRead := func(verb, name string) libraryapplyconfiguration.InputRead {
+		return libraryapplyconfiguration.InputRead{Verb: verb, Version: "v1", Resource: "pods", Namespace: "openshift-authentication", Name: name}
+	}
+	inputReads := &libraryapplyconfiguration.ApplyConfigurationInputReads{
+		ControllerReads: []libraryapplyconfiguration.ControllerInputReads{
+			{
+				ControllerName: "example",
+				Reads: []libraryapplyconfiguration.InputRead{
+					// dependents are found by listing the namespace
+					podRead("list", ""),
+					// owned through the replicaset of a recursive ownedResource
+					podRead("get", "oauth-openshift-6d8f7-x2k4p"),
+					// owned through a replicaset, but the ownedResource is not recursive
+					podRead("get", "other-5c9b4-q8m2z"),
+					// not owned
+					podRead("get", "standalone"),
+				},
+			},
+		},
+	}
+
+	actual, err := UndeclaredInputReads(context.Background(), inputReads, inputResources, filepath.Join(testDir, "input-dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &libraryapplyconfiguration.ApplyConfigurationInputReads{
+		ControllerReads: []libraryapplyconfiguration.ControllerInputReads{
+			{
+				ControllerName: "example",
+				Reads: []libraryapplyconfiguration.InputRead{
+					podRead("get", "other-5c9b4-q8m2z"),
+					podRead("get", "standalone"),
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("unexpected undeclared reads: %v", undeclaredInputReadsMessage(actual))
+	}
+}
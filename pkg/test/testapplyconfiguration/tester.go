@@ -115,13 +115,17 @@ func (o *TestApplyConfigurationOptions) Run(ctx context.Context) error {
 	}
 
 	failedTestsToOutput := map[string]string{}
+	inputReadsByBinary := map[string][]*libraryapplyconfiguration.ApplyConfigurationInputReads{}
 	for _, test := range o.Tests {
 		if ctx.Err() != nil {
 			// break the loop and report as much as we can.
 			break
 		}
-		currJunit := test.runTest(ctx, o.PreservePolicy)
+		currJunit, inputReads := test.runTest(ctx, o.PreservePolicy)
 		junit.TestCases = append(junit.TestCases, currJunit)
+		if inputReads != nil {
+			inputReadsByBinary[test.Description.BinaryName] = append(inputReadsByBinary[test.Description.BinaryName], inputReads)
+		}
 		if currJunit.FailureOutput != nil {
 			failedTestsToOutput[currJunit.Name] = fmt.Sprintf(
 				"\t%s\n\t\t%s",
@@ -138,6 +142,11 @@ func (o *TestApplyConfigurationOptions) Run(ctx context.Context) error {
 		}
 	}
 
+	// without any recorded reads every declared input would look unread, and replacing expected output is not a test run.
+	if len(inputReadsByBinary) > 0 && o.PreservePolicy != PreservePolicyReplaceExpectedOutput {
+		junit.TestCases = append(junit.TestCases, o.reportUnreadInputResources(ctx, inputReadsByBinary))
+	}
+
 	junitBytes, err := xml.MarshalIndent(junit, "", "    ")
 	if err != nil {
 		return err
@@ -164,12 +173,65 @@ func (o *TestApplyConfigurationOptions) Run(ctx context.Context) error {
 	return nil
 }
 
-func (o *TestOptions) runTest(ctx context.Context, preservePolicy PreservePolicy) *junitapi.JUnitTestCase {
+// reportUnreadInputResources writes the declared inputs that were never read by any test to unread-input-resources.yaml.
+// Unread inputs are reported, but do not fail the run, because a corpus may not exercise every controller.
+func (o *TestApplyConfigurationOptions) reportUnreadInputResources(ctx context.Context, inputReadsByBinary map[string][]*libraryapplyconfiguration.ApplyConfigurationInputReads) *junitapi.JUnitTestCase {
+	currJunit := &junitapi.JUnitTestCase{
+		Name: "unread input resources",
+	}
+
+	allUnreadInputResources := []UnreadInputResources{}
+	output := []string{}
+	for _, binaryName := range sets.List(sets.KeySet(inputReadsByBinary)) {
+		inputResources, err := applyconfiguration.ExecInputResources(ctx, binaryName)
+		if err != nil {
+			currJunit.FailureOutput = &junitapi.FailureOutput{
+				Message: fmt.Sprintf("unable to read input resources for %q", binaryName),
+				Output:  err.Error(),
+			}
+			return currJunit
+		}
+		unreadInputResources := UnreadInputResources{
+			BinaryName:      binaryName,
+			UnreadResources: UnreadApplyConfigurationResources(inputReadsByBinary[binaryName], inputResources),
+		}
+		allUnreadInputResources = append(allUnreadInputResources, unreadInputResources)
+		for _, curr := range unreadInputResources.UnreadResources {
+			output = append(output, fmt.Sprintf("binary %q: %v", binaryName, curr.FieldPath))
+		}
+	}
+	currJunit.SystemOut = strings.Join(output, "\n")
+
+	unreadInputResourcesBytes, err := yaml.Marshal(allUnreadInputResources)
+	if err != nil {
+		currJunit.FailureOutput = &junitapi.FailureOutput{
+			Message: "unable to marshal unread input resources",
+			Output:  err.Error(),
+		}
+		return currJunit
+	}
+	if err := os.WriteFile(filepath.Join(o.OutputDirectory, "unread-input-resources.yaml"), unreadInputResourcesBytes, 0644); err != nil {
+		currJunit.FailureOutput = &junitapi.FailureOutput{
+			Message: "unable to write unread input resources",
+			Output:  err.Error(),
+		}
+		return currJunit
+	}
+	if len(output) > 0 {
+		fmt.Fprintf(o.Streams.Out, "%d declared input resources were never read, see %s\n", len(output), filepath.Join(o.OutputDirectory, "unread-input-resources.yaml"))
+	}
+
+	return currJunit
+}
+
+// runTest returns the junit result and the reads apply-configuration made, if the output included them.
+func (o *TestOptions) runTest(ctx context.Context, preservePolicy PreservePolicy) (*junitapi.JUnitTestCase, *libraryapplyconfiguration.ApplyConfigurationInputReads) {
 	junitTestName := fmt.Sprintf("%v [Binary:%q] [Controllers:%q] [Directory:%q]", o.Description.TestName, o.Description.BinaryName, o.Description.Controllers, o.TestDirectory)
 	currJunit := &junitapi.JUnitTestCase{
 		Name: junitTestName,
 	}
 	startTime := now()
+	var inputReads *libraryapplyconfiguration.ApplyConfigurationInputReads
 
 	if preservePolicy == PreservePolicyReplaceExpectedOutput {
 		if err := os.RemoveAll(o.OutputDirectory); err != nil && !os.IsNotExist(err) {
@@ -177,7 +239,7 @@ func (o *TestOptions) runTest(ctx context.Context, preservePolicy PreservePolicy
 				Message: fmt.Sprintf("unable to delete output directory %q:\n%v\n", o.OutputDirectory, err),
 				Output:  fmt.Sprintf("unable to delete output directory %q:\n%v\n", o.OutputDirectory, err),
 			}
-			return currJunit
+			return currJunit, inputReads
 		}
 	}
 	if err := os.MkdirAll(o.OutputDirectory, 0755); err != nil {
@@ -185,7 +247,7 @@ func (o *TestOptions) runTest(ctx context.Context, preservePolicy PreservePolicy
 			Message: fmt.Sprintf("unable to create output directory %q:\n%v\n", o.OutputDirectory, err),
 			Output:  fmt.Sprintf("unable to create output directory %q:\n%v\n", o.OutputDirectory, err),
 		}
-		return currJunit
+		return currJunit, inputReads
 	}
 
 	inputDir := filepath.Join(o.TestDirectory, "input-dir")
@@ -197,12 +259,15 @@ func (o *TestOptions) runTest(ctx context.Context, preservePolicy PreservePolicy
 	}
 	actualResult, execErr := applyconfiguration.ExecApplyConfiguration(ctx, o.Description.BinaryName, args)
 	endTime := now()
+	if actualResult != nil {
+		inputReads = actualResult.InputReads()
+	}
 	currJunit.Duration = endTime.Sub(startTime).Round(1 * time.Second).Seconds()
 
 	if preservePolicy == PreservePolicyReplaceExpectedOutput {
 		os.Remove(filepath.Join(o.OutputDirectory, "stderr.log"))
 		os.Remove(filepath.Join(o.OutputDirectory, "stdout.log"))
-		return currJunit
+		return currJunit, inputReads
 	}
 
 	switch {
@@ -221,7 +286,7 @@ func (o *TestOptions) runTest(ctx context.Context, preservePolicy PreservePolicy
 			Message: "No result or error from apply-configuration",
 			Output:  "No result or error from apply-configuration",
 		}
-		return currJunit
+		return currJunit, inputReads
 
 	case execErr != nil && actualResult != nil:
 		currJunit.SystemOut = actualResult.Stdout()
@@ -242,7 +307,7 @@ func (o *TestOptions) runTest(ctx context.Context, preservePolicy PreservePolicy
 				Message: fmt.Sprintf("%v\n%v", execErr, currJunit.SystemErr),
 				Output:  fmt.Sprintf("ERROR:%v\n\nSTDERR:\n%s\n\nSTDOUT:\n:%s\n", execErr, currJunit.SystemErr, currJunit.SystemOut),
 			}
-			return currJunit
+			return currJunit, inputReads
 		}
 		// if the failure is an exit code failure, continue to test the output.
 		// TODO Perhaps we require some kind stderr matching?
@@ -254,21 +319,21 @@ func (o *TestOptions) runTest(ctx context.Context, preservePolicy PreservePolicy
 				Message: fmt.Sprintf("%v\n%v", execErr, currJunit.SystemErr),
 				Output:  fmt.Sprintf("ERROR:%v\n\nSTDERR:\n%s\n\nSTDOUT:\n:%s\n", execErr, currJunit.SystemErr, currJunit.SystemOut),
 			}
-			return currJunit
+			return currJunit, inputReads
 		}
 
 		var exitErr *exec.ExitError
 		if errors.As(execErr, &exitErr) && exitErr.ExitCode() != 0 {
 			// don't add the currJunit.FailureOutput so that this becomes a success
 			// TODO Perhaps we require some kind stderr matching?
-			return currJunit
+			return currJunit, inputReads
 		}
 
 		currJunit.FailureOutput = &junitapi.FailureOutput{
 			Message: fmt.Sprintf("%v\n%v", execErr, currJunit.SystemErr),
 			Output:  fmt.Sprintf("ERROR:%v\n\nSTDERR:\n%s\n\nSTDOUT:\n:%s\n", execErr, currJunit.SystemErr, currJunit.SystemOut),
 		}
-		return currJunit
+		return currJunit, inputReads
 	}
 
 	expectedOutputDir := filepath.Join(o.TestDirectory, "expected-output")
@@ -278,7 +343,7 @@ func (o *TestOptions) runTest(ctx context.Context, preservePolicy PreservePolicy
 			Message: fmt.Sprintf("failed to read expected output:\n%v\n", execErr),
 			Output:  fmt.Sprintf("failed to read expected output:\n%v\n", execErr),
 		}
-		return currJunit
+		return currJunit, inputReads
 	}
 	differences := libraryapplyconfiguration.EquivalentApplyConfigurationResultIgnoringEvents(expectedResult, actualResult)
 	if len(differences) > 0 {
@@ -286,10 +351,39 @@ func (o *TestOptions) runTest(ctx context.Context, preservePolicy PreservePolicy
 			Message: fmt.Sprintf("expected results mismatch %d times with actual results", len(differences)),
 			Output:  strings.Join(differences, "\n"),
 		}
-		return currJunit
+		return currJunit, inputReads
 	}
 
-	return currJunit
+	if currJunit.FailureOutput == nil {
+		if err := checkInputReads(ctx, o.Description.BinaryName, inputDir, actualResult); err != nil {
+			currJunit.FailureOutput = &junitapi.FailureOutput{
+				Message: "apply-configuration read resources that are not declared in input-resources",
+				Output:  err.Error(),
+			}
+			return currJunit, inputReads
+		}
+	}
+
+	return currJunit, inputReads
+}
+
+// checkInputReads fails if apply-configuration read something that the MOM would not provide in production.
+func checkInputReads(ctx context.Context, binaryName, inputDir string, actualResult libraryapplyconfiguration.ApplyConfigurationResult) error {
+	if actualResult.InputReads() == nil {
+		return nil
+	}
+	inputResources, err := applyconfiguration.ExecInputResources(ctx, binaryName)
+	if err != nil {
+		return err
+	}
+	undeclaredReads, err := UndeclaredInputReads(ctx, actualResult.InputReads(), inputResources, inputDir)
+	if err != nil {
+		return err
+	}
+	if len(undeclaredReads.ControllerReads) == 0 {
+		return nil
+	}
+	return errors.New(undeclaredInputReadsMessage(undeclaredReads))
 }
 
 var (